@@ -0,0 +1,117 @@
+// Package apperror รูปแบบ error เดียวของ API: code ที่ client ใช้ตัดสินใจได้, ข้อความตามภาษาผู้ใช้
+// และสาเหตุจริงที่บันทึกลง log เท่านั้น ไม่ส่งกลับไปให้ client
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Error ใช้เป็น sentinel ใน services ได้ (errors.Is เทียบด้วย Code)
+// WithDetail/WithField/Wrap คืนสำเนาใหม่เสมอ จึงไม่แก้ sentinel ที่ใช้ร่วมกัน
+type Error struct {
+	Code Code
+	// Detail ข้อความภาษาอังกฤษเพิ่มเติมที่ปลอดภัยจะแสดง เช่น field ไหนผิด
+	Detail string
+	// Fields ข้อมูลประกอบที่ client ใช้ได้ เช่น สถานะบัญชี
+	Fields map[string]interface{}
+	// Err สาเหตุจริง ใช้บันทึก log เท่านั้น
+	Err error
+}
+
+func New(code Code) *Error {
+	return &Error{Code: code}
+}
+
+// Wrap ผูกสาเหตุจริงไว้กับ code เช่น error จาก Firestore
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// Invalid คำขอที่ข้อมูลไม่ผ่านการตรวจสอบ detail จะถูกแสดงให้ client เห็น
+func Invalid(detail string) *Error {
+	return &Error{Code: CodeValidationFailed, Detail: detail}
+}
+
+// Internal error ฝั่ง server ข้อความ msg ใช้บอกว่าล้มเหลวตอนทำอะไร และบันทึกลง log เท่านั้น
+func Internal(err error, msg string) *Error {
+	if err == nil {
+		return &Error{Code: CodeInternal, Err: errors.New(msg)}
+	}
+	return &Error{Code: CodeInternal, Err: fmt.Errorf("%s: %w", msg, err)}
+}
+
+// OrInternal คืน err เดิมถ้าเป็น *Error อยู่แล้ว เช่น sentinel จาก services ไม่เช่นนั้นถือเป็น error ภายใน
+func OrInternal(err error, msg string) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err, msg)
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Code)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is ให้ errors.Is(err, services.ErrTaskNotFound) เป็นจริงแม้ err จะเป็นสำเนาที่เพิ่ม detail แล้ว
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func (e *Error) WithDetail(detail string) *Error {
+	c := e.clone()
+	c.Detail = detail
+	return c
+}
+
+func (e *Error) WithField(key string, value interface{}) *Error {
+	c := e.clone()
+	c.Fields = make(map[string]interface{}, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		c.Fields[k] = v
+	}
+	c.Fields[key] = value
+	return c
+}
+
+// Wrap ผูกสาเหตุจริงกับสำเนาของ e
+func (e *Error) Wrap(err error) *Error {
+	c := e.clone()
+	c.Err = err
+	return c
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	return &c
+}
+
+// From แปลง error ใดๆ เป็น *Error error ที่ไม่รู้จักถือเป็น error ภายในเสมอ
+func From(err error) *Error {
+	var appErr *Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(CodeTimeout, err)
+	case errors.Is(err, context.Canceled):
+		return Wrap(CodeRequestCanceled, err)
+	}
+	return Wrap(CodeInternal, err)
+}
@@ -0,0 +1,165 @@
+package apperror
+
+import "net/http"
+
+// Code รหัส error ที่คงที่ client ใช้ตัดสินใจได้โดยไม่ต้องอ่านข้อความ ห้ามเปลี่ยนชื่อรหัสที่ใช้อยู่แล้ว
+type Code string
+
+const (
+	CodeInvalidRequest       Code = "INVALID_REQUEST"
+	CodeValidationFailed     Code = "VALIDATION_FAILED"
+	CodeInvalidPageToken     Code = "INVALID_PAGE_TOKEN"
+	CodeUnauthorized         Code = "UNAUTHORIZED"
+	CodeForbidden            Code = "FORBIDDEN"
+	CodeAccessDenied         Code = "ACCESS_DENIED"
+	CodeNotFound             Code = "NOT_FOUND"
+	CodeRouteNotFound        Code = "ROUTE_NOT_FOUND"
+	CodeConflict             Code = "CONFLICT"
+	CodePayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRateLimited          Code = "RATE_LIMITED"
+	CodeRequestCanceled      Code = "REQUEST_CANCELED"
+	CodeTimeout              Code = "TIMEOUT"
+	CodeInternal             Code = "INTERNAL"
+
+	CodeAuthTokenMissing       Code = "AUTH_TOKEN_MISSING"
+	CodeAuthTokenInvalid       Code = "AUTH_TOKEN_INVALID"
+	CodeAuthRefreshRevoked     Code = "AUTH_REFRESH_TOKEN_REVOKED"
+	CodeAuthRefreshExpired     Code = "AUTH_REFRESH_TOKEN_EXPIRED"
+	CodeAuthRefreshInvalid     Code = "AUTH_REFRESH_TOKEN_INVALID"
+	CodeAuthInvalidPassword    Code = "AUTH_INVALID_PASSWORD"
+	CodeAuthAccountInactive    Code = "AUTH_ACCOUNT_INACTIVE"
+	CodeAuthAccountDeleted     Code = "AUTH_ACCOUNT_DELETED"
+	CodeAuthAccountUnverified  Code = "AUTH_ACCOUNT_UNVERIFIED"
+	CodeAuthEmailNotRegistered Code = "AUTH_EMAIL_NOT_REGISTERED"
+	CodeAuthEmailTaken         Code = "AUTH_EMAIL_TAKEN"
+	CodeAuthAdminRequired      Code = "AUTH_ADMIN_REQUIRED"
+	CodeCaptchaFailed          Code = "CAPTCHA_FAILED"
+
+	CodeOTPExpired          Code = "OTP_EXPIRED"
+	CodeOTPInvalid          Code = "OTP_INVALID"
+	CodeOTPAlreadyUsed      Code = "OTP_ALREADY_USED"
+	CodeOTPReferenceInvalid Code = "OTP_REFERENCE_INVALID"
+	CodeOTPBlocked          Code = "OTP_BLOCKED"
+
+	CodeUserNotFound            Code = "USER_NOT_FOUND"
+	CodeUserHasAssociations     Code = "USER_HAS_ASSOCIATIONS"
+	CodeBoardNotFound           Code = "BOARD_NOT_FOUND"
+	CodeBoardOwnerOnly          Code = "BOARD_OWNER_ONLY"
+	CodeTaskNotFound            Code = "TASK_NOT_FOUND"
+	CodeTaskUnknownStatus       Code = "TASK_UNKNOWN_STATUS"
+	CodeTaskConflict            Code = "TASK_CONFLICT"
+	CodeColumnNotEmpty          Code = "COLUMN_NOT_EMPTY"
+	CodeColumnTooLarge          Code = "COLUMN_TOO_LARGE"
+	CodeBatchTooLarge           Code = "BATCH_TOO_LARGE"
+	CodeLabelNotFound           Code = "LABEL_NOT_FOUND"
+	CodeLabelDuplicate          Code = "LABEL_DUPLICATE"
+	CodeLabelLimit              Code = "LABEL_LIMIT_REACHED"
+	CodeLabelUnknown            Code = "LABEL_NOT_IN_BOARD"
+	CodeChecklistNotFound       Code = "CHECKLIST_NOT_FOUND"
+	CodeCommentNotFound         Code = "COMMENT_NOT_FOUND"
+	CodeCommentForbidden        Code = "COMMENT_FORBIDDEN"
+	CodeCommentEditWindowClosed Code = "COMMENT_EDIT_WINDOW_CLOSED"
+	CodeAttachmentNotFound      Code = "ATTACHMENT_NOT_FOUND"
+	CodeAttachmentForbidden     Code = "ATTACHMENT_FORBIDDEN"
+	CodeAttachmentLimit         Code = "ATTACHMENT_LIMIT_REACHED"
+	CodeFileTooLarge            Code = "FILE_TOO_LARGE"
+	CodeFileUnsupportedType     Code = "FILE_UNSUPPORTED_TYPE"
+	CodeImportInvalid           Code = "IMPORT_INVALID"
+	CodeCalendarFeedNotFound    Code = "CALENDAR_FEED_NOT_FOUND"
+	CodeSearchEmptyQuery        Code = "SEARCH_EMPTY_QUERY"
+)
+
+type entry struct {
+	status int
+	en, th string
+}
+
+// catalog สถานะ HTTP และข้อความของแต่ละ code ใส่ให้ครบทั้งสองภาษาเสมอ
+//
+// สถานะที่เปลี่ยนจากเดิม: AUTH_ACCOUNT_INACTIVE และ AUTH_ACCOUNT_DELETED ตอบ 403 ทุก endpoint
+// (/auth/signin เดิมตอบ 401 และ 400) client เดิมควรแยกกรณีจาก code หรือ fields.status ("0"/"2") ที่ยังส่งเหมือนเดิม
+var catalog = map[Code]entry{
+	CodeInvalidRequest:       {http.StatusBadRequest, "The request is malformed.", "รูปแบบคำขอไม่ถูกต้อง"},
+	CodeValidationFailed:     {http.StatusBadRequest, "Some of the submitted data is invalid.", "ข้อมูลที่ส่งมาไม่ถูกต้อง"},
+	CodeInvalidPageToken:     {http.StatusBadRequest, "The page token is invalid. Request the first page again.", "page token ไม่ถูกต้อง กรุณาโหลดหน้าแรกใหม่"},
+	CodeUnauthorized:         {http.StatusUnauthorized, "Please sign in to continue.", "กรุณาเข้าสู่ระบบก่อนใช้งาน"},
+	CodeForbidden:            {http.StatusForbidden, "You are not allowed to do this.", "คุณไม่มีสิทธิ์ทำรายการนี้"},
+	CodeAccessDenied:         {http.StatusForbidden, "You do not have access to this item.", "คุณไม่มีสิทธิ์เข้าถึงรายการนี้"},
+	CodeNotFound:             {http.StatusNotFound, "The item was not found.", "ไม่พบข้อมูลที่ต้องการ"},
+	CodeRouteNotFound:        {http.StatusNotFound, "This endpoint does not exist.", "ไม่พบ endpoint นี้"},
+	CodeConflict:             {http.StatusConflict, "The item was changed by someone else. Please try again.", "ข้อมูลถูกแก้ไขโดยผู้อื่น กรุณาลองใหม่"},
+	CodePayloadTooLarge:      {http.StatusRequestEntityTooLarge, "The request is too large.", "คำขอมีขนาดใหญ่เกินไป"},
+	CodeUnsupportedMediaType: {http.StatusUnsupportedMediaType, "This content type is not supported.", "ไม่รองรับประเภทข้อมูลนี้"},
+	CodeRateLimited:          {http.StatusTooManyRequests, "Too many requests. Please try again later.", "มีการเรียกใช้งานบ่อยเกินไป กรุณาลองใหม่ภายหลัง"},
+	CodeRequestCanceled:      {499, "The request was canceled.", "คำขอถูกยกเลิก"},
+	CodeTimeout:              {http.StatusGatewayTimeout, "The request took too long. Please try again.", "ใช้เวลานานเกินไป กรุณาลองใหม่"},
+	CodeInternal:             {http.StatusInternalServerError, "Something went wrong. Please try again later.", "เกิดข้อผิดพลาดในระบบ กรุณาลองใหม่ภายหลัง"},
+
+	CodeAuthTokenMissing:       {http.StatusUnauthorized, "Authorization header is missing.", "ไม่พบ token สำหรับยืนยันตัวตน"},
+	CodeAuthTokenInvalid:       {http.StatusForbidden, "Your session is invalid or has expired. Please sign in again.", "เซสชันไม่ถูกต้องหรือหมดอายุ กรุณาเข้าสู่ระบบใหม่"},
+	CodeAuthRefreshRevoked:     {http.StatusForbidden, "Your session has been revoked. Please sign in again.", "เซสชันถูกยกเลิกแล้ว กรุณาเข้าสู่ระบบใหม่"},
+	CodeAuthRefreshExpired:     {http.StatusUnauthorized, "Your session has expired. Please sign in again.", "เซสชันหมดอายุ กรุณาเข้าสู่ระบบใหม่"},
+	CodeAuthRefreshInvalid:     {http.StatusUnauthorized, "Your session is invalid. Please sign in again.", "เซสชันไม่ถูกต้อง กรุณาเข้าสู่ระบบใหม่"},
+	CodeAuthInvalidPassword:    {http.StatusUnauthorized, "The password is incorrect.", "รหัสผ่านไม่ถูกต้อง"},
+	CodeAuthAccountInactive:    {http.StatusForbidden, "This account is not active.", "บัญชีผู้ใช้ไม่ได้เปิดใช้งาน"},
+	CodeAuthAccountDeleted:     {http.StatusForbidden, "This account has been deleted.", "บัญชีผู้ใช้ถูกลบแล้ว"},
+	CodeAuthAccountUnverified:  {http.StatusForbidden, "Please verify your email before signing in.", "กรุณายืนยันอีเมลก่อนเข้าสู่ระบบ"},
+	CodeAuthEmailNotRegistered: {http.StatusBadRequest, "This email is not registered.", "อีเมลนี้ยังไม่ได้ลงทะเบียน"},
+	CodeAuthEmailTaken:         {http.StatusConflict, "This email is already registered.", "อีเมลนี้ถูกใช้งานแล้ว"},
+	CodeAuthAdminRequired:      {http.StatusForbidden, "Administrator access is required.", "ต้องเป็นผู้ดูแลระบบเท่านั้น"},
+	CodeCaptchaFailed:          {http.StatusBadRequest, "reCAPTCHA verification failed.", "การยืนยัน reCAPTCHA ไม่สำเร็จ"},
+
+	CodeOTPExpired:          {http.StatusBadRequest, "The OTP has expired. Please request a new one.", "รหัส OTP หมดอายุแล้ว กรุณาขอรหัสใหม่"},
+	CodeOTPInvalid:          {http.StatusBadRequest, "The OTP is incorrect.", "รหัส OTP ไม่ถูกต้อง"},
+	CodeOTPAlreadyUsed:      {http.StatusBadRequest, "This OTP has already been used.", "รหัส OTP นี้ถูกใช้ไปแล้ว"},
+	CodeOTPReferenceInvalid: {http.StatusNotFound, "The reference code is invalid.", "รหัสอ้างอิงไม่ถูกต้อง"},
+	CodeOTPBlocked:          {http.StatusForbidden, "Too many OTP requests. Please try again later.", "ขอรหัส OTP บ่อยเกินไป กรุณาลองใหม่ภายหลัง"},
+
+	CodeUserNotFound:            {http.StatusNotFound, "User not found.", "ไม่พบผู้ใช้"},
+	CodeUserHasAssociations:     {http.StatusConflict, "The account still owns or belongs to boards or tasks.", "บัญชียังมีบอร์ดหรืองานที่เกี่ยวข้องอยู่"},
+	CodeBoardNotFound:           {http.StatusNotFound, "Board not found.", "ไม่พบบอร์ด"},
+	CodeBoardOwnerOnly:          {http.StatusForbidden, "Only the board owner can do this.", "เฉพาะเจ้าของบอร์ดเท่านั้นที่ทำรายการนี้ได้"},
+	CodeTaskNotFound:            {http.StatusNotFound, "Task not found.", "ไม่พบงาน"},
+	CodeTaskUnknownStatus:       {http.StatusBadRequest, "The status is not a column of this board.", "สถานะนี้ไม่ใช่คอลัมน์ของบอร์ด"},
+	CodeTaskConflict:            {http.StatusConflict, "The task was changed while saving. Please reload and try again.", "งานถูกเปลี่ยนระหว่างบันทึก กรุณาโหลดใหม่แล้วลองอีกครั้ง"},
+	CodeColumnNotEmpty:          {http.StatusConflict, "The column still has tasks. Move them before removing it.", "คอลัมน์ยังมีงานอยู่ กรุณาย้ายงานออกก่อนลบ"},
+	CodeColumnTooLarge:          {http.StatusConflict, "The column has too many tasks to reorder.", "คอลัมน์มีงานมากเกินกว่าจะจัดลำดับได้"},
+	CodeBatchTooLarge:           {http.StatusBadRequest, "The batch is too large. Please split it into smaller requests.", "รายการมีจำนวนมากเกินไป กรุณาแบ่งส่งหลายครั้ง"},
+	CodeLabelNotFound:           {http.StatusNotFound, "Label not found.", "ไม่พบป้ายกำกับ"},
+	CodeLabelDuplicate:          {http.StatusConflict, "A label with this name already exists.", "มีป้ายกำกับชื่อนี้อยู่แล้ว"},
+	CodeLabelLimit:              {http.StatusBadRequest, "The board already has the maximum number of labels.", "บอร์ดมีป้ายกำกับครบจำนวนสูงสุดแล้ว"},
+	CodeLabelUnknown:            {http.StatusBadRequest, "The label does not belong to this board.", "ป้ายกำกับนี้ไม่ได้อยู่ในบอร์ด"},
+	CodeChecklistNotFound:       {http.StatusNotFound, "Checklist item not found.", "ไม่พบรายการตรวจสอบ"},
+	CodeCommentNotFound:         {http.StatusNotFound, "Comment not found.", "ไม่พบความคิดเห็น"},
+	CodeCommentForbidden:        {http.StatusForbidden, "You can only change your own comments.", "แก้ไขหรือลบได้เฉพาะความคิดเห็นของตัวเอง"},
+	CodeCommentEditWindowClosed: {http.StatusForbidden, "This comment can no longer be edited.", "ไม่สามารถแก้ไขความคิดเห็นนี้ได้แล้ว"},
+	CodeAttachmentNotFound:      {http.StatusNotFound, "Attachment not found.", "ไม่พบไฟล์แนบ"},
+	CodeAttachmentForbidden:     {http.StatusForbidden, "Only the uploader or the board owner can delete this attachment.", "เฉพาะผู้อัปโหลดหรือเจ้าของบอร์ดเท่านั้นที่ลบไฟล์แนบนี้ได้"},
+	CodeAttachmentLimit:         {http.StatusBadRequest, "The task already has the maximum number of attachments.", "งานนี้มีไฟล์แนบครบจำนวนสูงสุดแล้ว"},
+	CodeFileTooLarge:            {http.StatusRequestEntityTooLarge, "The file is too large.", "ไฟล์มีขนาดใหญ่เกินไป"},
+	CodeFileUnsupportedType:     {http.StatusUnsupportedMediaType, "This file type is not supported.", "ไม่รองรับไฟล์ประเภทนี้"},
+	CodeImportInvalid:           {http.StatusUnprocessableEntity, "The file could not be imported.", "ไม่สามารถนำเข้าไฟล์นี้ได้"},
+	CodeCalendarFeedNotFound:    {http.StatusNotFound, "Calendar feed not found.", "ไม่พบปฏิทินที่แชร์"},
+	CodeSearchEmptyQuery:        {http.StatusBadRequest, "The search has no searchable words.", "คำค้นหาไม่มีคำที่ค้นหาได้"},
+}
+
+// Status สถานะ HTTP ของ code ที่ไม่รู้จักถือเป็น 500
+func Status(code Code) int {
+	if e, ok := catalog[code]; ok {
+		return e.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message ข้อความของ code ตามภาษา ("th" หรือ "en")
+func Message(code Code, lang string) string {
+	e, ok := catalog[code]
+	if !ok {
+		e = catalog[CodeInternal]
+	}
+	if lang == LangThai {
+		return e.th
+	}
+	return e.en
+}
@@ -0,0 +1,51 @@
+package apperror
+
+import (
+	"myapp/dto"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+const (
+	LangEnglish = "en"
+	LangThai    = "th"
+)
+
+var languages = language.NewMatcher([]language.Tag{language.English, language.Thai})
+
+// Language เลือกภาษาของข้อความจาก Accept-Language ค่าเริ่มต้นเป็นภาษาอังกฤษ
+func Language(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return LangEnglish
+	}
+	_, index, _ := languages.Match(tags...)
+	if index == 1 {
+		return LangThai
+	}
+	return LangEnglish
+}
+
+// Abort ส่ง err ให้ middleware.ErrorHandler ตอบกลับ แล้วหยุด handler ที่เหลือ
+// handler ควร return ทันทีหลังเรียก
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// Respond เขียน error response ทันที ใช้ใน middleware.ErrorHandler และที่ที่ไม่ผ่าน middleware นั้น
+func Respond(c *gin.Context, err error) {
+	appErr := From(err)
+	c.AbortWithStatusJSON(Status(appErr.Code), Body(c, appErr))
+}
+
+func Body(c *gin.Context, appErr *Error) dto.ErrorResponse {
+	return dto.ErrorResponse{
+		Code:      string(appErr.Code),
+		Error:     Message(appErr.Code, Language(c.GetHeader("Accept-Language"))),
+		Detail:    appErr.Detail,
+		Fields:    appErr.Fields,
+		RequestID: c.GetString("requestId"),
+	}
+}
@@ -0,0 +1,19 @@
+// Package blobstore เก็บไฟล์แนบของ task แยกจาก Firestore
+// ใช้ LocalStore ตอนพัฒนา และ FirebaseStore บน production
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Store ที่เก็บไฟล์ อ้างอิงไฟล์ด้วย key เช่น "tasks/<taskid>/<attachmentid>"
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete ไม่คืน error ถ้าไม่มีไฟล์อยู่แล้ว
+	Delete(ctx context.Context, key string) error
+}
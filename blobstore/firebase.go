@@ -0,0 +1,59 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	gcs "cloud.google.com/go/storage"
+	firebase "firebase.google.com/go"
+)
+
+// FirebaseStore เก็บไฟล์ใน Firebase Storage (Cloud Storage bucket)
+type FirebaseStore struct {
+	bucket *gcs.BucketHandle
+}
+
+// NewFirebaseStore ใช้ bucket ตาม StorageBucket ใน config ของ app
+func NewFirebaseStore(ctx context.Context, app *firebase.App) (*FirebaseStore, error) {
+	client, err := app.Storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := client.DefaultBucket()
+	if err != nil {
+		return nil, err
+	}
+	return &FirebaseStore{bucket: bucket}, nil
+}
+
+func (s *FirebaseStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	// ยกเลิก context เมื่อคัดลอกไม่สำเร็จ เพื่อไม่ให้ไฟล์ที่อัปโหลดไม่ครบถูกบันทึก
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	if _, err := io.Copy(w, r); err != nil {
+		cancel()
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *FirebaseStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.bucket.Object(key).NewReader(ctx)
+	if errors.Is(err, gcs.ErrObjectNotExist) {
+		return nil, ErrNotFound
+	}
+	return reader, err
+}
+
+func (s *FirebaseStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, gcs.ErrObjectNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+
+	"myapp/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Traced ห่อ Store ให้ทุกการเรียกมี span ของตัวเอง
+func Traced(store Store) Store {
+	return tracedStore{store}
+}
+
+type tracedStore struct {
+	store Store
+}
+
+func (s tracedStore) Put(ctx context.Context, key string, r io.Reader, contentType string) (err error) {
+	ctx, span := tracing.Start(ctx, "blobstore.Put", attribute.String("blob.key", key), attribute.String("blob.content_type", contentType))
+	defer func() { tracing.End(span, err) }()
+	return s.store.Put(ctx, key, r, contentType)
+}
+
+func (s tracedStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, span := tracing.Start(ctx, "blobstore.Get", attribute.String("blob.key", key))
+	defer func() { tracing.End(span, err) }()
+	return s.store.Get(ctx, key)
+}
+
+func (s tracedStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "blobstore.Delete", attribute.String("blob.key", key))
+	defer func() { tracing.End(span, err) }()
+	return s.store.Delete(ctx, key)
+}
@@ -0,0 +1,131 @@
+// migrate-allday แปลง startdate และ duedate ของ task แบบทั้งวันที่เคยเก็บเป็นเที่ยงคืนตาม timezone ของผู้สร้าง
+// ให้เป็นเที่ยงคืน UTC ของวันเดียวกัน รันแบบ dry-run ก่อน แล้วใช้ -apply เพื่อเขียนจริง
+// รันซ้ำได้ ค่าที่เป็นเที่ยงคืน UTC อยู่แล้วจะไม่ถูกแก้
+//
+//	go run ./cmd/migrate-allday [-apply]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"myapp/config"
+	"myapp/connection"
+	"myapp/model"
+	"myapp/services"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+type change struct {
+	ref     *firestore.DocumentRef
+	updates []firestore.Update
+}
+
+func main() {
+	apply := flag.Bool("apply", false, "write changes to Firestore (default is dry-run)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := connection.FBConnection(cfg.Firebase)
+	if err != nil {
+		log.Fatalf("failed to connect to Firestore: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	changes, err := migrateTasks(ctx, client)
+	if err != nil {
+		log.Fatalf("tasks: %v", err)
+	}
+
+	for _, ch := range changes {
+		for _, u := range ch.updates {
+			fmt.Printf("%s: %s = %v\n", ch.ref.Path, u.Path, u.Value)
+		}
+	}
+	fmt.Printf("%d document(s) to update\n", len(changes))
+	if !*apply || len(changes) == 0 {
+		return
+	}
+
+	bw := client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(changes))
+	for _, ch := range changes {
+		job, err := bw.Update(ch.ref, ch.updates)
+		if err != nil {
+			log.Fatalf("failed to queue %s: %v", ch.ref.Path, err)
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+
+	failed := 0
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			log.Printf("failed to update %s: %v", changes[i].ref.Path, err)
+			failed++
+		}
+	}
+	fmt.Printf("updated %d document(s), %d failed\n", len(jobs)-failed, failed)
+}
+
+func migrateTasks(ctx context.Context, client *firestore.Client) ([]change, error) {
+	docs, err := client.Collection("Tasks").Where("allday", "==", true).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]*time.Location)
+	var changes []change
+	for _, doc := range docs {
+		var task model.Tasks
+		if err := doc.DataTo(&task); err != nil {
+			log.Printf("Tasks/%s: %v, skipped", doc.Ref.ID, err)
+			continue
+		}
+
+		loc, ok := locations[task.CreatedBy]
+		if !ok {
+			loc = creatorLocation(ctx, client, task.CreatedBy)
+			locations[task.CreatedBy] = loc
+		}
+
+		var updates []firestore.Update
+		for _, field := range []struct {
+			path  string
+			value *time.Time
+		}{{"startdate", task.StartDate}, {"duedate", task.DueDate}} {
+			if field.value == nil || isUTCMidnight(*field.value) {
+				continue
+			}
+			updates = append(updates, firestore.Update{Path: field.path, Value: services.AllDayDate(*field.value, loc)})
+		}
+		if len(updates) > 0 {
+			changes = append(changes, change{ref: doc.Ref, updates: updates})
+		}
+	}
+	return changes, nil
+}
+
+// creatorLocation timezone ของผู้สร้าง task ถ้าหาผู้ใช้ไม่พบจะใช้ DefaultTimezone
+func creatorLocation(ctx context.Context, client *firestore.Client, userID string) *time.Location {
+	if userID != "" {
+		if loc, err := services.GetUserLocation(ctx, client, userID); err == nil {
+			return loc
+		}
+	}
+	loc, _ := services.LoadLocation(services.DefaultTimezone)
+	return loc
+}
+
+// isUTCMidnight ค่าที่แปลงแล้ว เที่ยงคืนตาม timezone อื่นไม่มีทางตรงกับเที่ยงคืน UTC
+func isUTCMidnight(t time.Time) bool {
+	t = t.UTC()
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
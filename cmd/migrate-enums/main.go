@@ -0,0 +1,267 @@
+// migrate-enums แปลงค่า status, priority, active, verify และ type ของเอกสารเดิม
+// ให้เป็นค่าที่ model รองรับ และเติม position ให้ task เดิม รันแบบ dry-run ก่อน แล้วใช้ -apply เพื่อเขียนจริง
+//
+//	go run ./cmd/migrate-enums [-apply]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"myapp/config"
+	"myapp/connection"
+	"myapp/model"
+	"myapp/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+type change struct {
+	ref     *firestore.DocumentRef
+	updates []firestore.Update
+}
+
+func main() {
+	apply := flag.Bool("apply", false, "write changes to Firestore (default is dry-run)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := connection.FBConnection(cfg.Firebase)
+	if err != nil {
+		log.Fatalf("failed to connect to Firestore: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	var changes []change
+
+	userChanges, err := migrateUsers(ctx, client)
+	if err != nil {
+		log.Fatalf("users: %v", err)
+	}
+	changes = append(changes, userChanges...)
+
+	boards, boardChanges, err := migrateBoards(ctx, client)
+	if err != nil {
+		log.Fatalf("boards: %v", err)
+	}
+	changes = append(changes, boardChanges...)
+
+	taskChanges, err := migrateTasks(ctx, client, boards)
+	if err != nil {
+		log.Fatalf("tasks: %v", err)
+	}
+	changes = append(changes, taskChanges...)
+
+	for _, ch := range changes {
+		for _, u := range ch.updates {
+			fmt.Printf("%s: %s = %v\n", ch.ref.Path, u.Path, u.Value)
+		}
+	}
+	fmt.Printf("%d document(s) to update\n", len(changes))
+	if !*apply || len(changes) == 0 {
+		return
+	}
+
+	bw := client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(changes))
+	for _, ch := range changes {
+		job, err := bw.Update(ch.ref, ch.updates)
+		if err != nil {
+			log.Fatalf("failed to queue %s: %v", ch.ref.Path, err)
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+
+	failed := 0
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			log.Printf("failed to update %s: %v", changes[i].ref.Path, err)
+			failed++
+		}
+	}
+	fmt.Printf("updated %d document(s), %d failed\n", len(jobs)-failed, failed)
+}
+
+func migrateUsers(ctx context.Context, client *firestore.Client) ([]change, error) {
+	docs, err := client.Collection("Users").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []change
+	for _, doc := range docs {
+		data := doc.Data()
+		var updates []firestore.Update
+
+		// ไม่มีค่า active เดิมถือว่าใช้งานได้ เหมือนที่ signin เคยทำ
+		if value, ok := normalizeFlag(data["active"], string(model.AccountActive)); ok && value != data["active"] {
+			updates = append(updates, firestore.Update{Path: "active", Value: model.AccountStatus(value)})
+		} else if !ok {
+			log.Printf("Users/%s: unknown active %v, skipped", doc.Ref.ID, data["active"])
+		}
+		value, ok := normalizeFlag(data["verify"], string(model.VerifyPending))
+		ok = ok && model.VerifyStatus(value).IsValid()
+		if ok && value != data["verify"] {
+			updates = append(updates, firestore.Update{Path: "verify", Value: model.VerifyStatus(value)})
+		} else if !ok {
+			log.Printf("Users/%s: unknown verify %v, skipped", doc.Ref.ID, data["verify"])
+		}
+
+		if len(updates) > 0 {
+			changes = append(changes, change{ref: doc.Ref, updates: updates})
+		}
+	}
+	return changes, nil
+}
+
+// normalizeFlag แปลง bool, ตัวเลข หรือ "true"/"false" ให้เป็น "0"/"1"/"2"
+func normalizeFlag(value interface{}, fallback string) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return fallback, true
+	case bool:
+		if v {
+			return "1", true
+		}
+		return "0", true
+	case int64:
+		return normalizeFlag(strconv.FormatInt(v, 10), fallback)
+	case float64:
+		return normalizeFlag(strconv.FormatFloat(v, 'f', -1, 64), fallback)
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "":
+			return fallback, true
+		case "0", "false":
+			return "0", true
+		case "1", "true":
+			return "1", true
+		case "2":
+			return "2", true
+		}
+	}
+	return "", false
+}
+
+func migrateBoards(ctx context.Context, client *firestore.Client) (map[string]*model.Board, []change, error) {
+	docs, err := client.Collection("Boards").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	boards := make(map[string]*model.Board, len(docs))
+	var changes []change
+	for _, doc := range docs {
+		data := doc.Data()
+		board := &model.Board{BoardID: doc.Ref.ID}
+		if raw, ok := data["columns"]; ok && raw != nil {
+			if err := doc.DataTo(board); err != nil {
+				log.Printf("Boards/%s: %v", doc.Ref.ID, err)
+			}
+		}
+		boards[doc.Ref.ID] = board
+
+		boardType, _ := data["type"].(string)
+		if model.BoardType(boardType).IsValid() {
+			continue
+		}
+
+		// ค่าเดิมอย่าง "unknown" ตัดสินจากว่ามีสมาชิกใน BoardUser หรือไม่
+		members, err := client.Collection("BoardUser").Where("boardid", "==", doc.Ref.ID).Limit(1).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, nil, err
+		}
+		value := model.BoardPrivate
+		if len(members) > 0 {
+			value = model.BoardGroup
+		}
+		changes = append(changes, change{ref: doc.Ref, updates: []firestore.Update{{Path: "type", Value: value}}})
+	}
+	return boards, changes, nil
+}
+
+func migrateTasks(ctx context.Context, client *firestore.Client, boards map[string]*model.Board) ([]change, error) {
+	docs, err := client.Collection("Tasks").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []change
+	for _, doc := range docs {
+		data := doc.Data()
+		var updates []firestore.Update
+
+		boardID, _ := data["boardid"].(string)
+		board := boards[boardID]
+		if board == nil {
+			board = &model.Board{BoardID: boardID}
+		}
+		columns := services.BoardColumns(board)
+
+		status := model.TaskStatus(stringValue(data["status"]))
+		column, _, ok := services.FindColumn(columns, status)
+		if !ok {
+			legacy, valid := services.ParseImportStatus(string(status))
+			if !valid {
+				log.Printf("Tasks/%s: unknown status %v, skipped", doc.Ref.ID, data["status"])
+				continue
+			}
+			column = services.ColumnForLegacyStatus(board, legacy)
+		}
+		if string(column.ColumnID) != data["status"] {
+			updates = append(updates, firestore.Update{Path: "status", Value: column.ColumnID})
+		}
+
+		if priority, valid := services.ParseImportPriority(stringValue(data["priority"])); !valid {
+			log.Printf("Tasks/%s: unknown priority %v, cleared", doc.Ref.ID, data["priority"])
+			updates = append(updates, firestore.Update{Path: "priority", Value: firestore.Delete})
+		} else if priority == model.PriorityNone && data["priority"] != nil {
+			updates = append(updates, firestore.Update{Path: "priority", Value: firestore.Delete})
+		} else if priority != model.PriorityNone && string(priority) != data["priority"] {
+			updates = append(updates, firestore.Update{Path: "priority", Value: priority})
+		}
+
+		// task ที่อยู่ในคอลัมน์ done แต่ยังไม่มี completedat ใช้เวลาแก้ไขล่าสุดแทน
+		if _, has := data["completedat"]; column.Done && !has {
+			completedAt, ok := data["updatedat"].(time.Time)
+			if !ok {
+				completedAt = time.Now()
+			}
+			updates = append(updates, firestore.Update{Path: "completedat", Value: completedAt})
+		}
+
+		// task ที่สร้างก่อนมีคอลัมน์ไม่มี position จึงไม่อยู่ในผลของ query ที่ OrderBy position
+		if _, has := data["position"]; !has {
+			createdAt, _ := data["createdat"].(time.Time)
+			updates = append(updates, firestore.Update{Path: "position", Value: services.InitialPosition(createdAt)})
+		}
+
+		if len(updates) > 0 {
+			changes = append(changes, change{ref: doc.Ref, updates: updates})
+		}
+	}
+	return changes, nil
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
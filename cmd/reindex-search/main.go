@@ -0,0 +1,65 @@
+// reindex-search สร้างดัชนีค้นหาใหม่จาก Boards และ Tasks ทั้งหมด
+// ใช้ครั้งแรกหลังเปิดใช้การค้นหา หรือเมื่อดัชนีไม่ตรงกับข้อมูล
+//
+//	go run ./cmd/reindex-search
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"myapp/config"
+	"myapp/connection"
+	"myapp/model"
+	"myapp/search"
+	"myapp/services"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := connection.FBConnection(cfg.Firebase)
+	if err != nil {
+		log.Fatalf("failed to connect to Firestore: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	index := search.NewFirestoreIndex(client)
+
+	boards, err := client.Collection("Boards").Documents(ctx).GetAll()
+	if err != nil {
+		log.Fatalf("boards: %v", err)
+	}
+	var docs []search.Document
+	for _, snap := range boards {
+		var board model.Board
+		if err := snap.DataTo(&board); err != nil {
+			log.Printf("Boards/%s: %v", snap.Ref.ID, err)
+			continue
+		}
+		board.BoardID = snap.Ref.ID
+		docs = append(docs, services.BoardSearchDocument(&board))
+	}
+
+	tasks, err := client.Collection("Tasks").Documents(ctx).GetAll()
+	if err != nil {
+		log.Fatalf("tasks: %v", err)
+	}
+	for _, snap := range tasks {
+		var task model.Tasks
+		if err := snap.DataTo(&task); err != nil {
+			log.Printf("Tasks/%s: %v", snap.Ref.ID, err)
+			continue
+		}
+		task.TaskID = snap.Ref.ID
+		docs = append(docs, services.TaskSearchDocument(&task))
+	}
+
+	if err := index.Put(ctx, docs...); err != nil {
+		log.Fatalf("failed to index: %v", err)
+	}
+	fmt.Printf("indexed %d board(s) and %d task(s)\n", len(boards), len(tasks))
+}
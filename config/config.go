@@ -0,0 +1,360 @@
+// Package config โหลดค่าตั้งค่าของแอปครั้งเดียวตอนเริ่มต้น
+// ลำดับความสำคัญ (หลังทับก่อน): ค่าเริ่มต้น < configs/<APP_ENV>.yaml < .env.<APP_ENV> / .env < environment variables
+package config
+
+import (
+	"errors"
+	"fmt"
+	"myapp/model"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+
+	// minProductionSecretLength กันการใช้ secret สั้นๆ แบบตอนพัฒนาบน production
+	minProductionSecretLength = 32
+)
+
+type Config struct {
+	Env       string            `yaml:"-"`
+	Server    ServerConfig      `yaml:"server"`
+	Workers   WorkerConfig      `yaml:"workers"`
+	JWT       JWTConfig         `yaml:"jwt"`
+	Firebase  FirebaseConfig    `yaml:"firebase"`
+	Storage   StorageConfig     `yaml:"storage"`
+	SMTP      model.EmailConfig `yaml:"smtp"`
+	Captcha   CaptchaConfig     `yaml:"captcha"`
+	Log       LogConfig         `yaml:"log"`
+	Metrics   MetricsConfig     `yaml:"metrics"`
+	Tracing   TracingConfig     `yaml:"tracing"`
+	RateLimit RateLimitConfig   `yaml:"rate_limit"`
+}
+
+// ServerConfig timeout ของ http.Server และเวลาที่รอให้คำขอที่ค้างอยู่เสร็จตอนปิด server
+// TrustedProxies CIDR ของ load balancer ที่เชื่อ X-Forwarded-For ได้ ว่างคือใช้ IP ของ connection เสมอ
+// เพราะ rate limit ที่นับต่อ IP จะถูกหลบได้ถ้าปลอม header นี้ได้
+// PublicURL URL ที่ผู้ใช้เข้าถึง API ได้ เช่น https://api.example.com ใช้สร้างลิงก์ที่ส่งออกไปนอกแอป เช่น calendar feed
+type ServerConfig struct {
+	Port              string        `yaml:"port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	TrustedProxies    []string      `yaml:"trusted_proxies"`
+	PublicURL         string        `yaml:"public_url"`
+}
+
+// WorkerConfig งานเบื้องหลัง ReminderInterval เป็น 0 หมายถึงไม่ส่ง reminder จาก instance นี้
+type WorkerConfig struct {
+	ReminderInterval time.Duration `yaml:"reminder_interval"`
+	MailWorkers      int           `yaml:"mail_workers"`
+	MailQueueSize    int           `yaml:"mail_queue_size"`
+}
+
+// LogConfig Level เป็น debug, info, warn หรือ error และ Format เป็น text หรือ json
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// MetricsConfig Token ว่างหมายถึงเปิด /metrics โดยไม่ต้องยืนยันตัวตน (เช่นเมื่อกันไว้ที่ network)
+type MetricsConfig struct {
+	Token string `yaml:"token"`
+}
+
+// TracingConfig Exporter เป็น none, stdout หรือ otlp
+// ค่าอื่นของ OpenTelemetry เช่น OTEL_EXPORTER_OTLP_ENDPOINT และ OTEL_TRACES_SAMPLER อ่านจาก environment โดย SDK
+type TracingConfig struct {
+	Exporter string `yaml:"exporter"`
+}
+
+// ชื่อกลุ่มของการจำกัดอัตราคำขอใน RateLimitConfig.Rules
+const (
+	// RateLimitAPI ทุก endpoint ใต้ /v1 นับต่อ IP
+	RateLimitAPI = "api"
+	// RateLimitAuth endpoint ใต้ /v1/auth นับต่อ IP
+	RateLimitAuth = "auth"
+	// RateLimitAccount เข้าสู่ระบบ สมัคร และค้นหาบัญชี นับต่ออีเมลที่ส่งมา กันการไล่เดาอีเมลหรือรหัสผ่านจากหลาย IP
+	RateLimitAccount = "account"
+	// RateLimitUserSearch ค้นหาผู้ใช้ นับต่อผู้ใช้
+	RateLimitUserSearch = "user_search"
+)
+
+// RateLimitConfig Enabled เป็นเท็จคือไม่จำกัดเลย กลุ่มที่ไม่มีใน Rules ก็ไม่ถูกจำกัด
+type RateLimitConfig struct {
+	Enabled bool                     `yaml:"enabled"`
+	Rules   map[string]RateLimitRule `yaml:"rules"`
+}
+
+// RateLimitRule token bucket ที่เติม PerMinute ครั้งต่อนาทีและสะสมได้ไม่เกิน Burst ครั้ง
+type RateLimitRule struct {
+	PerMinute int `yaml:"per_minute"`
+	Burst     int `yaml:"burst"`
+}
+
+type JWTConfig struct {
+	AccessSecret  string `yaml:"access_secret"`
+	RefreshSecret string `yaml:"refresh_secret"`
+}
+
+type FirebaseConfig struct {
+	CredentialsFile string `yaml:"credentials_file"`
+	StorageBucket   string `yaml:"storage_bucket"`
+}
+
+// StorageConfig ที่เก็บไฟล์แนบ Driver เป็น "local" หรือ "firebase"
+type StorageConfig struct {
+	Driver   string `yaml:"driver"`
+	LocalDir string `yaml:"local_dir"`
+}
+
+type CaptchaConfig struct {
+	ProjectID       string `yaml:"project_id"`
+	SiteKey         string `yaml:"site_key"`
+	CredentialsFile string `yaml:"credentials_file"`
+}
+
+func defaults() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              "8080",
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Workers: WorkerConfig{
+			ReminderInterval: time.Minute,
+			MailWorkers:      2,
+			MailQueueSize:    100,
+		},
+		Storage: StorageConfig{Driver: "local", LocalDir: "uploads"},
+		Log:     LogConfig{Level: "info", Format: "text"},
+		Tracing: TracingConfig{Exporter: "none"},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Rules: map[string]RateLimitRule{
+				RateLimitAPI:        {PerMinute: 600, Burst: 200},
+				RateLimitAuth:       {PerMinute: 30, Burst: 10},
+				RateLimitAccount:    {PerMinute: 5, Burst: 5},
+				RateLimitUserSearch: {PerMinute: 20, Burst: 20},
+			},
+		},
+	}
+}
+
+// Load อ่านและตรวจสอบค่าตั้งค่าทั้งหมด คืน error รวมทุกค่าที่ขาดหรือไม่ถูกต้อง
+// APP_ENV เลือก profile (ค่าเริ่มต้น development) และ CONFIG_FILE ใช้ระบุไฟล์ YAML เอง
+func Load() (*Config, error) {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = EnvDevelopment
+	}
+
+	cfg := defaults()
+	cfg.Env = env
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = filepath.Join("configs", env+".yaml")
+	}
+	if err := cfg.loadYAML(path, os.Getenv("CONFIG_FILE") != ""); err != nil {
+		return nil, err
+	}
+
+	// godotenv ไม่ทับตัวแปรที่มีอยู่แล้ว environment จริงจึงสำคัญกว่าไฟล์ .env
+	for _, file := range []string{".env." + env, ".env"} {
+		if err := godotenv.Load(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load %s: %w", file, err)
+		}
+	}
+	for name, field := range cfg.envBindings() {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+	var problems []error
+	for name, field := range cfg.durationBindings() {
+		if value := os.Getenv(name); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("%s must be a duration such as 30s, got %q", name, value))
+				continue
+			}
+			*field = d
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+	// ชื่อ exporter ไม่สนตัวพิมพ์ เก็บเป็นตัวเล็กเพื่อให้ tracing.Setup เทียบได้ตรง
+	cfg.Tracing.Exporter = strings.ToLower(strings.TrimSpace(cfg.Tracing.Exporter))
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadYAML ไม่มีไฟล์ profile ก็ได้ ยกเว้นกรณีที่ระบุผ่าน CONFIG_FILE
+func (c *Config) loadYAML(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// envBindings ชื่อ environment variable ของแต่ละค่า (ใช้ชื่อเดิมที่ deploy อยู่แล้ว)
+func (c *Config) envBindings() map[string]*string {
+	return map[string]*string{
+		"PORT":                             &c.Server.Port,
+		"PUBLIC_URL":                       &c.Server.PublicURL,
+		"JWT_SECRET_KEY":                   &c.JWT.AccessSecret,
+		"JWT_REFRESH_SECRET_KEY":           &c.JWT.RefreshSecret,
+		"GOOGLE_APPLICATION_CREDENTIALS_1": &c.Firebase.CredentialsFile,
+		"FIREBASE_STORAGE_BUCKET":          &c.Firebase.StorageBucket,
+		"STORAGE_DRIVER":                   &c.Storage.Driver,
+		"STORAGE_LOCAL_DIR":                &c.Storage.LocalDir,
+		"SMTP_HOST":                        &c.SMTP.Host,
+		"SMTP_PORT":                        &c.SMTP.Port,
+		"SMTP_USERNAME":                    &c.SMTP.Username,
+		"SMTP_PASSWORD":                    &c.SMTP.Password,
+		"GOOGLE_CLOUD_PROJECT_ID":          &c.Captcha.ProjectID,
+		"RECAPTCHA_SITE_KEY":               &c.Captcha.SiteKey,
+		"GOOGLE_APPLICATION_CREDENTIALS_2": &c.Captcha.CredentialsFile,
+		"LOG_LEVEL":                        &c.Log.Level,
+		"LOG_FORMAT":                       &c.Log.Format,
+		"METRICS_TOKEN":                    &c.Metrics.Token,
+		"TRACING_EXPORTER":                 &c.Tracing.Exporter,
+	}
+}
+
+func (c *Config) durationBindings() map[string]*time.Duration {
+	return map[string]*time.Duration{
+		"SERVER_READ_TIMEOUT":        &c.Server.ReadTimeout,
+		"SERVER_READ_HEADER_TIMEOUT": &c.Server.ReadHeaderTimeout,
+		"SERVER_WRITE_TIMEOUT":       &c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        &c.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":           &c.Server.ShutdownTimeout,
+		"REMINDER_INTERVAL":          &c.Workers.ReminderInterval,
+	}
+}
+
+func (c *Config) Validate() error {
+	var problems []error
+	require := func(name, value string) {
+		if value == "" {
+			problems = append(problems, fmt.Errorf("%s is required", name))
+		}
+	}
+
+	require("JWT_SECRET_KEY", c.JWT.AccessSecret)
+	require("JWT_REFRESH_SECRET_KEY", c.JWT.RefreshSecret)
+	require("GOOGLE_APPLICATION_CREDENTIALS_1", c.Firebase.CredentialsFile)
+	// นอก production รันได้โดยไม่มี SMTP และ reCAPTCHA (ส่งอีเมลและตรวจ captcha จะล้มเหลวตอนเรียกใช้)
+	if c.IsProduction() {
+		require("PUBLIC_URL", c.Server.PublicURL)
+		require("SMTP_HOST", c.SMTP.Host)
+		require("SMTP_PORT", c.SMTP.Port)
+		require("SMTP_USERNAME", c.SMTP.Username)
+		require("SMTP_PASSWORD", c.SMTP.Password)
+		require("GOOGLE_CLOUD_PROJECT_ID", c.Captcha.ProjectID)
+		require("RECAPTCHA_SITE_KEY", c.Captcha.SiteKey)
+		require("GOOGLE_APPLICATION_CREDENTIALS_2", c.Captcha.CredentialsFile)
+	}
+
+	if c.Server.PublicURL != "" {
+		if u, err := url.Parse(c.Server.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Errorf("PUBLIC_URL must be an absolute http(s) URL, got %q", c.Server.PublicURL))
+		}
+	}
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		problems = append(problems, fmt.Errorf("PORT must be a number, got %q", c.Server.Port))
+	}
+	if c.SMTP.Port != "" {
+		if _, err := strconv.Atoi(c.SMTP.Port); err != nil {
+			problems = append(problems, fmt.Errorf("SMTP_PORT must be a number, got %q", c.SMTP.Port))
+		}
+	}
+
+	for name, d := range c.durationBindings() {
+		if *d < 0 {
+			problems = append(problems, fmt.Errorf("%s must not be negative", name))
+		}
+	}
+	if c.Server.ShutdownTimeout == 0 {
+		problems = append(problems, errors.New("SHUTDOWN_TIMEOUT must be greater than 0"))
+	}
+	if c.Workers.MailWorkers < 1 || c.Workers.MailQueueSize < 1 {
+		problems = append(problems, errors.New("workers.mail_workers and workers.mail_queue_size must be at least 1"))
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		require("STORAGE_LOCAL_DIR", c.Storage.LocalDir)
+	case "firebase":
+		require("FIREBASE_STORAGE_BUCKET", c.Firebase.StorageBucket)
+	default:
+		problems = append(problems, fmt.Errorf("STORAGE_DRIVER must be local or firebase, got %q", c.Storage.Driver))
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		problems = append(problems, fmt.Errorf("LOG_FORMAT must be text or json, got %q", c.Log.Format))
+	}
+
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "none", "stdout", "otlp":
+	default:
+		problems = append(problems, fmt.Errorf("TRACING_EXPORTER must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+
+	for name, rule := range c.RateLimit.Rules {
+		if rule.PerMinute < 1 || rule.Burst < 1 {
+			problems = append(problems, fmt.Errorf("rate_limit.rules.%s per_minute and burst must be at least 1", name))
+		}
+	}
+
+	if c.Env == EnvProduction {
+		if n := len(c.JWT.AccessSecret); n > 0 && n < minProductionSecretLength {
+			problems = append(problems, fmt.Errorf("JWT_SECRET_KEY must be at least %d characters in production", minProductionSecretLength))
+		}
+		if n := len(c.JWT.RefreshSecret); n > 0 && n < minProductionSecretLength {
+			problems = append(problems, fmt.Errorf("JWT_REFRESH_SECRET_KEY must be at least %d characters in production", minProductionSecretLength))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+	return nil
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func validConfig(env string) *Config {
+	c := defaults()
+	c.Env = env
+	c.JWT.AccessSecret = strings.Repeat("a", minProductionSecretLength)
+	c.JWT.RefreshSecret = strings.Repeat("b", minProductionSecretLength)
+	c.Firebase.CredentialsFile = "firebase.json"
+	return c
+}
+
+func withProductionServices(c *Config) *Config {
+	c.Server.PublicURL = "https://api.example.com"
+	c.SMTP.Host = "smtp.example.com"
+	c.SMTP.Port = "587"
+	c.SMTP.Username = "user"
+	c.SMTP.Password = "secret"
+	c.Captcha.ProjectID = "project"
+	c.Captcha.SiteKey = "site-key"
+	c.Captcha.CredentialsFile = "captcha.json"
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{name: "development without SMTP and reCAPTCHA", cfg: validConfig(EnvDevelopment)},
+		{name: "production without SMTP", cfg: validConfig(EnvProduction), wantErr: "SMTP_HOST is required"},
+		{name: "production without reCAPTCHA", cfg: func() *Config {
+			c := withProductionServices(validConfig(EnvProduction))
+			c.Captcha.SiteKey = ""
+			return c
+		}(), wantErr: "RECAPTCHA_SITE_KEY is required"},
+		{name: "production with everything", cfg: withProductionServices(validConfig(EnvProduction))},
+		{name: "production without public URL", cfg: func() *Config {
+			c := withProductionServices(validConfig(EnvProduction))
+			c.Server.PublicURL = ""
+			return c
+		}(), wantErr: "PUBLIC_URL is required"},
+		{name: "relative public URL", cfg: func() *Config {
+			c := validConfig(EnvDevelopment)
+			c.Server.PublicURL = "api.example.com"
+			return c
+		}(), wantErr: "PUBLIC_URL must be an absolute"},
+		{name: "short production secret", cfg: func() *Config {
+			c := withProductionServices(validConfig(EnvProduction))
+			c.JWT.AccessSecret = "short"
+			return c
+		}(), wantErr: "JWT_SECRET_KEY must be at least"},
+		{name: "uppercase exporter", cfg: func() *Config {
+			c := validConfig(EnvDevelopment)
+			c.Tracing.Exporter = "OTLP"
+			return c
+		}()},
+		{name: "unknown exporter", cfg: func() *Config {
+			c := validConfig(EnvDevelopment)
+			c.Tracing.Exporter = "jaeger"
+			return c
+		}(), wantErr: "TRACING_EXPORTER must be"},
+		{name: "invalid SMTP port", cfg: func() *Config {
+			c := validConfig(EnvDevelopment)
+			c.SMTP.Port = "smtp"
+			return c
+		}(), wantErr: "SMTP_PORT must be a number"},
+		{name: "zero rate limit", cfg: func() *Config {
+			c := validConfig(EnvDevelopment)
+			c.RateLimit.Rules = map[string]RateLimitRule{RateLimitAPI: {PerMinute: 0, Burst: 1}}
+			return c
+		}(), wantErr: "rate_limit.rules.api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadNormalizesTracingExporter(t *testing.T) {
+	t.Setenv("APP_ENV", EnvDevelopment)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JWT_SECRET_KEY", "access")
+	t.Setenv("JWT_REFRESH_SECRET_KEY", "refresh")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS_1", "firebase.json")
+	t.Setenv("TRACING_EXPORTER", " Stdout ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Tracing.Exporter != "stdout" {
+		t.Fatalf("exporter = %q, want %q", cfg.Tracing.Exporter, "stdout")
+	}
+}
@@ -0,0 +1,39 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"myapp/blobstore"
+	"myapp/config"
+
+	firebase "firebase.google.com/go"
+	"google.golang.org/api/option"
+)
+
+// NewBlobStore เลือกที่เก็บไฟล์แนบจาก cfg.Storage.Driver ("local" หรือ "firebase")
+func NewBlobStore(cfg *config.Config) (blobstore.Store, error) {
+	store, err := newBlobStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return blobstore.Traced(store), nil
+}
+
+func newBlobStore(cfg *config.Config) (blobstore.Store, error) {
+	switch cfg.Storage.Driver {
+	case "local":
+		return blobstore.NewLocalStore(cfg.Storage.LocalDir)
+
+	case "firebase":
+		ctx := context.Background()
+		app, err := firebase.NewApp(ctx, &firebase.Config{StorageBucket: cfg.Firebase.StorageBucket},
+			option.WithCredentialsFile(cfg.Firebase.CredentialsFile))
+		if err != nil {
+			return nil, err
+		}
+		return blobstore.NewFirebaseStore(ctx, app)
+
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q", cfg.Storage.Driver)
+	}
+}
@@ -3,45 +3,51 @@ package connection
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"myapp/config"
+	"myapp/metrics"
 
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go"
-	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var FirestoreClient *firestore.Client
 
-func FBConnection() (*firestore.Client, error) {
-	// Load .env file
-	err := godotenv.Load()
-	if err != nil {
-		fmt.Println("Warning: No .env file found or failed to load") // Use only in dev
-	}
-
-	// Get the path to the service account key from the environment variable
-	serviceAccountKeyPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_1")
-	if serviceAccountKeyPath == "" {
-		return nil, fmt.Errorf("environment variable GOOGLE_APPLICATION_CREDENTIALS is not set")
-	}
-
+func FBConnection(cfg config.FirebaseConfig) (*firestore.Client, error) {
 	ctx := context.Background()
 
 	// Initialize Firebase app with Firestore
-	app, err := firebase.NewApp(ctx, nil, option.WithCredentialsFile(serviceAccountKeyPath))
+	app, err := firebase.NewApp(ctx, nil,
+		option.WithCredentialsFile(cfg.CredentialsFile),
+		// นับจำนวนและเวลาของทุก RPC ไปยัง Firestore แยกตาม collection
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(metrics.FirestoreUnaryInterceptor())),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(metrics.FirestoreStreamInterceptor())),
+		// span ของแต่ละ RPC ต่อจาก span ของคำขอใน ctx
+		option.WithGRPCDialOption(grpc.WithStatsHandler(otelgrpc.NewClientHandler())),
+	)
 	if err != nil {
-		log.Fatalf("error initializing app: %v\n", err)
-		return nil, err
+		return nil, fmt.Errorf("error initializing app: %w", err)
 	}
 
 	client, err := app.Firestore(ctx)
 	if err != nil {
-		log.Fatalf("error getting Firestore client: %v\n", err)
-		return nil, err
+		return nil, fmt.Errorf("error getting Firestore client: %w", err)
 	}
 
-	fmt.Println("Firestore connection successful")
+	slog.Info("firestore connection successful")
 	return client, nil
 }
+
+// PingFirestore อ่านเอกสารที่ไม่มีอยู่จริง ถ้าได้ NotFound แปลว่าเชื่อมต่อและยืนยันสิทธิ์ได้
+func PingFirestore(ctx context.Context, client *firestore.Client) error {
+	_, err := client.Collection("Health").Doc("ping").Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	return err
+}
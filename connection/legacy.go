@@ -0,0 +1,125 @@
+package connection
+
+import (
+	"net/http"
+	"strings"
+)
+
+// legacyRoute path เดิมก่อนมี /v1 ที่แอปมือถือรุ่นเก่ายังเรียกอยู่
+// from/to เป็น template: ":name" แทน segment เดียว และ "*rest" ท้าย path แทน segment ที่เหลือทั้งหมด
+// method ว่างคือทุก method
+type legacyRoute struct {
+	method string
+	from   string
+	to     string
+}
+
+// legacyRoutes ตรวจตามลำดับ รายการแรกที่ตรงจะถูกใช้ ดังนั้น path เฉพาะต้องอยู่ก่อน path ที่มี *rest
+var legacyRoutes = []legacyRoute{
+	{http.MethodPost, "/auth/IdentityOTP", "/v1/auth/otp/identity"},
+	{http.MethodPost, "/auth/resetpasswordOTP", "/v1/auth/otp/password-reset"},
+	{http.MethodPost, "/auth/sendemail", "/v1/auth/otp/send"},
+	{http.MethodPost, "/auth/resendotp", "/v1/auth/otp/resend"},
+	{http.MethodPut, "/auth/verifyOTP", "/v1/auth/otp/verify"},
+	{http.MethodPost, "/auth/newaccesstoken", "/v1/auth/token/refresh"},
+	{http.MethodPut, "/auth/resetpassword", "/v1/auth/password"},
+	{http.MethodPost, "/auth/googlelogin", "/v1/auth/google"},
+	{"", "/auth/*rest", "/v1/auth/*rest"},
+	{http.MethodPost, "/email", "/v1/auth/lookup"},
+
+	{http.MethodPut, "/user/profile", "/v1/users/me"},
+	{http.MethodDelete, "/user/account", "/v1/users/me"},
+	{"", "/user/search", "/v1/users/search"},
+
+	{"", "/board", "/v1/boards"},
+	{"", "/board/*rest", "/v1/boards/*rest"},
+	{"", "/task", "/v1/tasks"},
+	{"", "/task/*rest", "/v1/tasks/*rest"},
+	{"", "/tasks/*rest", "/v1/tasks/*rest"},
+
+	{"", "/myday", "/v1/my-day"},
+	{"", "/agenda", "/v1/agenda"},
+	{"", "/search", "/v1/search"},
+
+	{"", "/calendar", "/v1/calendar"},
+	{"", "/calendar/rotate", "/v1/calendar/rotate"},
+	{"", "/calendar/feed/:secret", "/v1/calendar/feeds/:secret"},
+	{"", "/calendar/feed/:secret/board/:boardid", "/v1/calendar/feeds/:secret/boards/:boardid"},
+}
+
+// legacyAliases เขียน path เดิมเป็น path ใต้ /v1 ก่อนส่งให้ router จึงผ่าน middleware ชุดเดียวกันครั้งเดียว
+// และบอก client ว่า path นี้เลิกใช้แล้วด้วย header Deprecation กับ Link ไปยัง path ใหม่
+func legacyAliases(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v1/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		path, ok := rewriteLegacyPath(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+path+`>; rel="successor-version"`)
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = path
+		r2.URL.RawPath = ""
+		next.ServeHTTP(w, r2)
+	})
+}
+
+func rewriteLegacyPath(method, path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range legacyRoutes {
+		if route.method != "" && route.method != method {
+			continue
+		}
+		params, ok := matchTemplate(route.from, segments)
+		if !ok {
+			continue
+		}
+		return expandTemplate(route.to, params), true
+	}
+	return "", false
+}
+
+func matchTemplate(template string, segments []string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(template, "/"), "/")
+	params := map[string]string{}
+	for i, part := range parts {
+		if strings.HasPrefix(part, "*") {
+			if i >= len(segments) {
+				return nil, false
+			}
+			params[part] = strings.Join(segments[i:], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		switch {
+		case strings.HasPrefix(part, ":"):
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[part] = segments[i]
+		case part != segments[i]:
+			return nil, false
+		}
+	}
+	return params, len(parts) == len(segments)
+}
+
+func expandTemplate(template string, params map[string]string) string {
+	parts := strings.Split(template, "/")
+	for i, part := range parts {
+		if value, ok := params[part]; ok {
+			parts[i] = value
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,40 @@
+package connection
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRewriteLegacyPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{name: "exact route", method: http.MethodPost, path: "/auth/IdentityOTP", want: "/v1/auth/otp/identity", wantOK: true},
+		{name: "specific route before wildcard", method: http.MethodPost, path: "/auth/googlelogin", want: "/v1/auth/google", wantOK: true},
+		{name: "method mismatch falls through to wildcard", method: http.MethodGet, path: "/auth/googlelogin", want: "/v1/auth/googlelogin", wantOK: true},
+		{name: "wildcard keeps the rest of the path", method: http.MethodGet, path: "/board/abc/tasks", want: "/v1/boards/abc/tasks", wantOK: true},
+		{name: "bare collection", method: http.MethodGet, path: "/board", want: "/v1/boards", wantOK: true},
+		{name: "trailing slash", method: http.MethodGet, path: "/myday/", want: "/v1/my-day", wantOK: true},
+		{name: "method-specific route", method: http.MethodDelete, path: "/user/account", want: "/v1/users/me", wantOK: true},
+		{name: "wrong method without wildcard", method: http.MethodGet, path: "/user/account"},
+		{name: "single parameter", method: http.MethodGet, path: "/calendar/feed/s3cret", want: "/v1/calendar/feeds/s3cret", wantOK: true},
+		{name: "two parameters", method: http.MethodGet, path: "/calendar/feed/s3cret/board/b1",
+			want: "/v1/calendar/feeds/s3cret/boards/b1", wantOK: true},
+		{name: "empty parameter", method: http.MethodGet, path: "/calendar/feed//board/b1"},
+		{name: "extra segment", method: http.MethodGet, path: "/search/more"},
+		{name: "unknown path", method: http.MethodGet, path: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rewriteLegacyPath(tt.method, tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("rewriteLegacyPath(%s %s) = %q, %v, want %q, %v", tt.method, tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
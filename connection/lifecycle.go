@@ -0,0 +1,34 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// shutdownStep ส่วนประกอบที่ต้องปิดตอนหยุด server
+type shutdownStep struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// shutdown ปิดทีละขั้นตามลำดับภายในเวลา timeout รวม
+// ขั้นที่ล้มเหลวจะไม่หยุดขั้นถัดไป เพื่อให้ทรัพยากรที่เหลือยังถูกปิด
+func shutdown(timeout time.Duration, steps ...shutdownStep) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, step := range steps {
+		if err := step.close(ctx); err != nil {
+			slog.Error("failed to stop component", "component", step.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+		}
+	}
+	if len(errs) == 0 {
+		slog.Info("server stopped gracefully")
+	}
+	return errors.Join(errs...)
+}
@@ -1,23 +1,158 @@
 package connection
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myapp/apperror"
+	"myapp/config"
+	agenda "myapp/controller/agenda"
 	auth "myapp/controller/auth"
 	board "myapp/controller/board"
+	calendar "myapp/controller/calendar"
+	healthapi "myapp/controller/health"
+	metricsapi "myapp/controller/metrics"
+	notification "myapp/controller/notification"
+	openapiapi "myapp/controller/openapi"
+	searchapi "myapp/controller/search"
 	task "myapp/controller/task"
 	user "myapp/controller/user"
+	"myapp/health"
+	"myapp/mailer"
+	"myapp/metrics"
+	"myapp/middleware"
+	"myapp/openapi"
+	"myapp/ratelimit"
+	"myapp/scheduler"
+	"myapp/search"
+	"myapp/services"
+	"myapp/tracing"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"cloud.google.com/go/firestore"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func StartServer() {
-	router := gin.Default()
+const (
+	healthCheckTimeout = 3 * time.Second
+	healthCacheTTL     = 5 * time.Second
+)
+
+// StartServer รัน HTTP server และงานเบื้องหลังจนกว่าจะได้รับ SIGINT/SIGTERM
+// จากนั้นปิดตามลำดับ: รอคำขอที่ค้างอยู่ -> หยุด scheduler -> ส่งอีเมลในคิวให้หมด -> ปิด Firestore client -> ส่ง span ที่ค้าง
+func StartServer(cfg *config.Config) error {
+	middleware.Configure(cfg.JWT)
+	middleware.ConfigureRateLimit(cfg.RateLimit, ratelimit.NewMemoryStore())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+
+	fb, err := FBConnection(cfg.Firebase)
+	if err != nil {
+		shutdownTracing(context.Background())
+		return err
+	}
+
+	mail := mailer.NewQueue(func(msg mailer.Message) error {
+		return services.SendingEmail(context.Background(), cfg.SMTP, msg.To, msg.Subject, msg.Body)
+	}, cfg.Workers.MailWorkers, cfg.Workers.MailQueueSize)
+	metrics.MailQueueDepth(mail.Len)
 
-	fb, err := FBConnection()
+	jobs := scheduler.New()
+	if cfg.Workers.ReminderInterval > 0 {
+		jobs.Every("reminders", cfg.Workers.ReminderInterval, func(ctx context.Context) error {
+			_, err := services.DeliverDueReminders(ctx, fb, mail, time.Now())
+			return err
+		})
+	}
+
+	checker := health.NewChecker(healthCheckTimeout, healthCacheTTL)
+	checker.Register("firestore", func(ctx context.Context) error { return PingFirestore(ctx, fb) })
+	checker.RegisterOptional("mail_queue", mail.Check)
+	if cfg.SMTP.Host != "" {
+		checker.RegisterOptional("smtp", func(ctx context.Context) error { return services.CheckSMTP(ctx, cfg.SMTP) })
+	}
+	checker.RegisterOptional("scheduler", jobs.Check)
+
+	router, err := newRouter(cfg, fb, checker)
 	if err != nil {
-		log.Fatalf("Failed to initialize Firestore client: %v", err)
+		fb.Close()
+		shutdownTracing(context.Background())
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           legacyAliases(router),
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// scheduler ใช้ context แยก เพื่อให้ยังทำงานอยู่ระหว่างรอคำขอที่ค้าง
+	jobs.Start(context.Background())
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down server")
+	case runErr = <-serveErr:
+		slog.Error("server stopped", "error", runErr)
 	}
+	stop()
+
+	err = shutdown(cfg.Server.ShutdownTimeout,
+		shutdownStep{"http server", srv.Shutdown},
+		shutdownStep{"scheduler", jobs.Stop},
+		shutdownStep{"mail queue", mail.Close},
+		shutdownStep{"firestore", func(context.Context) error { return fb.Close() }},
+		shutdownStep{"tracing", shutdownTracing},
+	)
+	return errors.Join(runErr, err)
+}
+
+func newRouter(cfg *config.Config, fb *firestore.Client, checker *health.Checker) (*gin.Engine, error) {
+	router := gin.New()
+	// ไม่ได้ระบุ proxy ก็ไม่เชื่อ X-Forwarded-For เลย ไม่อย่างนั้น client ปลอม IP เพื่อหลบ rate limit ได้
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.Use(
+		middleware.RequestID(),
+		otelgin.Middleware(tracing.ServiceName, otelgin.WithGinFilter(traceRequest)),
+		middleware.RequestLogger("/healthz", "/readyz", "/metrics"),
+		middleware.Metrics(),
+		middleware.ErrorHandler(),
+		middleware.Recovery(),
+	)
+	router.NoRoute(func(c *gin.Context) {
+		apperror.Abort(c, apperror.New(apperror.CodeRouteNotFound))
+	})
+
+	healthapi.HealthController(router, checker)
+	metricsapi.MetricsController(router, cfg.Metrics.Token)
+	openapiapi.OpenAPIController(router)
 
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "Api is running!"})
@@ -25,18 +160,61 @@ func StartServer() {
 
 	router.Use(cors.Default())
 
-	auth.SignInController(router, fb)
-	auth.SignUpController(router, fb)
-	auth.OTPController(router, fb)
-	auth.CaptchaController(router, fb)
-	auth.SignUpGetEmailController(router, fb)
-	auth.GoogleSignInController(router, fb)
+	store, err := NewBlobStore(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize blob store: %w", err)
+	}
+	index := search.NewFirestoreIndex(fb)
+
+	// ทุก endpoint ของแอปอยู่ใต้ /v1 path เดิมถูกเขียนเป็น path ใหม่ใน legacyAliases
+	v1 := router.Group("/v1", middleware.RateLimit(config.RateLimitAPI, middleware.ByIP))
+	authRoutes := v1.Group("", middleware.RateLimit(config.RateLimitAuth, middleware.ByIP))
+
+	auth.SignInController(authRoutes, fb, cfg)
+	auth.SignUpController(authRoutes, fb)
+	auth.OTPController(authRoutes, fb, cfg)
+	auth.CaptchaController(authRoutes, fb, cfg)
+	auth.SignUpGetEmailController(authRoutes, fb)
+	auth.GoogleSignInController(authRoutes, fb, cfg)
 
-	user.UserController(router, fb)
+	user.UserController(v1, fb)
 
-	board.CreateBoardController(router, fb)
+	board.ListBoardController(v1, fb)
+	board.CreateBoardController(v1, fb, index)
+	board.ImportTaskController(v1, fb, index)
+	board.DeleteBoardController(v1, fb, store, index)
+	board.LabelController(v1, fb)
+	board.BoardTaskController(v1, fb)
+	board.ColumnController(v1, fb)
 
-	task.CreateTaskController(router, fb)
+	task.CreateTaskController(v1, fb, index)
+	task.GetTaskController(v1, fb)
+	task.UpdateTaskController(v1, fb, index)
+	task.ChecklistController(v1, fb)
+	task.BatchTaskController(v1, fb, store, index)
+	task.CommentController(v1, fb)
+	task.AttachmentController(v1, fb, store)
+	task.TaskLabelController(v1, fb)
+	task.TaskAssigneeController(v1, fb)
+	task.MoveTaskController(v1, fb)
 
-	router.Run()
+	agenda.AgendaController(v1, fb)
+	notification.NotificationController(v1, fb)
+	calendar.CalendarController(v1, fb, cfg)
+	searchapi.SearchController(v1, fb, index)
+
+	for _, route := range openapi.Undocumented(router.Routes()) {
+		slog.Warn("route does not match openapi document", "route", route)
+	}
+
+	return router, nil
+}
+
+// traceRequest ไม่สร้าง trace ให้ probe และ calendar feed ที่มี secret อยู่ใน path
+func traceRequest(c *gin.Context) bool {
+	switch c.FullPath() {
+	case "/healthz", "/readyz", "/metrics", "/v1/calendar/feeds/:secret", "/v1/calendar/feeds/:secret/boards/:boardid":
+		return false
+	}
+	return true
 }
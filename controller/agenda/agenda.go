@@ -0,0 +1,147 @@
+package agenda
+
+import (
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/services"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAgendaDays จำกัดช่วงวันที่ขอได้ต่อครั้ง
+const maxAgendaDays = 62
+
+func AgendaController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.GET("/agenda", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		GetAgenda(c, firestoreClient)
+	})
+	router.GET("/my-day", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		GetMyDay(c, firestoreClient)
+	})
+}
+
+func GetAgenda(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+
+	ctx := c.Request.Context()
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	today := services.StartOfDay(time.Now(), loc)
+	from := today
+	if value := c.Query("from"); value != "" {
+		from, err = time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			apperror.Abort(c, apperror.Invalid("Invalid from date, expected YYYY-MM-DD"))
+			return
+		}
+	}
+
+	// to เป็นวันสุดท้ายที่รวมอยู่ในผลลัพธ์
+	to := from.AddDate(0, 0, 6)
+	if value := c.Query("to"); value != "" {
+		to, err = time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			apperror.Abort(c, apperror.Invalid("Invalid to date, expected YYYY-MM-DD"))
+			return
+		}
+	}
+	if to.Before(from) {
+		apperror.Abort(c, apperror.Invalid("to must not be before from"))
+		return
+	}
+	if to.Sub(from) > maxAgendaDays*24*time.Hour {
+		apperror.Abort(c, apperror.Invalid("Date range must not exceed 62 days"))
+		return
+	}
+
+	respondAgenda(c, firestoreClient, userId, from, to, loc)
+}
+
+func GetMyDay(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+
+	ctx := c.Request.Context()
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	today := services.StartOfDay(time.Now(), loc)
+	respondAgenda(c, firestoreClient, userId, today, today, loc)
+}
+
+func respondAgenda(c *gin.Context, firestoreClient *firestore.Client, userId string, from, to time.Time, loc *time.Location) {
+	ctx := c.Request.Context()
+
+	boardIDs, err := services.GetAccessibleBoardIDs(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+
+	tasks, err := services.GetTasksByBoardIDs(ctx, firestoreClient, boardIDs)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+		return
+	}
+	tasks = services.FilterTasksByLabels(tasks, services.ParseIDList(c.QueryArray("label")))
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	notifications, err := services.GetNotificationsByTaskIDs(ctx, firestoreClient, taskIDs)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get reminders"))
+		return
+	}
+
+	end := to.AddDate(0, 0, 1)
+	agenda := services.BuildAgenda(tasks, notifications, from, end, time.Now(), loc)
+
+	response := dto.AgendaResponse{
+		Timezone: loc.String(),
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Overdue:  toAgendaItems(agenda.Overdue, loc),
+		Days:     make([]dto.AgendaDay, 0, len(agenda.Days)),
+	}
+	for _, day := range agenda.Days {
+		response.Days = append(response.Days, dto.AgendaDay{
+			Date:  day.Date.Format("2006-01-02"),
+			Items: toAgendaItems(day.Entries, loc),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func toAgendaItems(entries []services.AgendaEntry, loc *time.Location) []dto.AgendaItem {
+	items := make([]dto.AgendaItem, 0, len(entries))
+	for _, entry := range entries {
+		occursAt := entry.OccursAt
+		items = append(items, dto.AgendaItem{
+			TaskID:     entry.Task.TaskID,
+			BoardID:    entry.Task.BoardID,
+			TaskName:   entry.Task.TaskName,
+			Status:     entry.Task.Status,
+			Priority:   entry.Task.Priority,
+			DueDate:    services.FormatTaskTime(&occursAt, entry.Task.AllDay, loc),
+			AllDay:     entry.Task.AllDay,
+			Overdue:    entry.Overdue,
+			Pattern:    entry.Pattern,
+			Occurrence: entry.Occurrence,
+			Labels:     dto.EmptyIfNil(entry.Task.Labels),
+		})
+	}
+	return items
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"myapp/apperror"
+	"myapp/config"
 	"myapp/dto"
+	"myapp/metrics"
 	"myapp/middleware"
 	"myapp/model"
 	"myapp/services"
+	"myapp/tracing"
 	"net/http"
 	"time"
 
@@ -18,28 +22,28 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func OTPController(router *gin.Engine, firestoreClient *firestore.Client) {
+func OTPController(router gin.IRouter, firestoreClient *firestore.Client, cfg *config.Config) {
 	routes := router.Group("/auth")
 	{
-		routes.POST("/IdentityOTP", func(c *gin.Context) {
+		routes.POST("/otp/identity", func(c *gin.Context) {
 			IdentityOTP(c, firestoreClient)
 		})
-		routes.POST("/resetpasswordOTP", func(c *gin.Context) {
+		routes.POST("/otp/password-reset", func(c *gin.Context) {
 			ResetpasswordOTP(c, firestoreClient)
 		})
-		routes.POST("/sendemail", func(c *gin.Context) {
-			Sendemail(c, firestoreClient)
+		routes.POST("/otp/send", func(c *gin.Context) {
+			Sendemail(c, firestoreClient, cfg)
 		})
-		routes.POST("/resendotp", func(c *gin.Context) {
-			ResendOTP(c, firestoreClient)
+		routes.POST("/otp/resend", func(c *gin.Context) {
+			ResendOTP(c, firestoreClient, cfg)
 		})
-		routes.PUT("/verifyOTP", func(c *gin.Context) {
-			VerifyOTP(c, firestoreClient)
+		routes.PUT("/otp/verify", func(c *gin.Context) {
+			VerifyOTP(c, firestoreClient, cfg)
 		})
-		routes.POST("/newaccesstoken", middleware.RefreshTokenMiddleware(), func(c *gin.Context) {
-			NewAccessToken(c, firestoreClient)
+		routes.POST("/token/refresh", middleware.RefreshTokenMiddleware(), func(c *gin.Context) {
+			NewAccessToken(c, firestoreClient, cfg)
 		})
-		routes.PUT("/resetpassword", func(c *gin.Context) {
+		routes.PUT("/password", func(c *gin.Context) {
 			ResetPassword(c, firestoreClient)
 		})
 	}
@@ -48,41 +52,41 @@ func OTPController(router *gin.Engine, firestoreClient *firestore.Client) {
 func IdentityOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	var req dto.IdentityOTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// ตรวจสอบว่าอีเมลนี้มีอยู่ในระบบหรือไม่
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	exists, err := services.UserExist(ctx, firestoreClient, req.Email)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check existing email"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check existing email"))
 		return
 	}
 	if !exists {
-		c.JSON(400, gin.H{"error": "Email is not already registered"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthEmailNotRegistered))
 		return
 	}
 
 	// ตรวจสอบว่าอีเมลถูกบล็อกหรือไม่
 	blocked, err := services.IsEmailBlocked(c, firestoreClient, req.Email, "verify")
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check email status"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check email status"))
 		return
 	}
 	if blocked {
-		c.JSON(403, gin.H{"error": "Too many OTP requests. Please try again later."})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPBlocked))
 		return
 	}
 
 	// ตรวจสอบจำนวนครั้งที่ขอ OTP และบล็อกถ้าเกินกำหนด
 	shouldBlock, err := services.CheckAndBlockIfNeeded(c, firestoreClient, req.Email, "verify")
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check OTP request count"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check OTP request count"))
 		return
 	}
 	if shouldBlock {
-		c.JSON(403, gin.H{"error": "Too many OTP requests. Your email has been blocked temporarily."})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPBlocked))
 		return
 	}
 
@@ -97,41 +101,41 @@ func IdentityOTP(c *gin.Context, firestoreClient *firestore.Client) {
 func ResetpasswordOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	var req dto.IdentityOTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// ตรวจสอบว่าอีเมลนี้มีอยู่ในระบบหรือไม่
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	exists, err := services.UserExist(ctx, firestoreClient, req.Email)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check existing email"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check existing email"))
 		return
 	}
 	if !exists {
-		c.JSON(400, gin.H{"error": "Email is not already registered"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthEmailNotRegistered))
 		return
 	}
 
 	// ตรวจสอบว่าอีเมลถูกบล็อกหรือไม่
 	blocked, err := services.IsEmailBlocked(c, firestoreClient, req.Email, "resetpassword")
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check email status"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check email status"))
 		return
 	}
 	if blocked {
-		c.JSON(403, gin.H{"error": "Too many OTP requests. Please try again later."})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPBlocked))
 		return
 	}
 
 	// ตรวจสอบจำนวนครั้งที่ขอ OTP และบล็อกถ้าเกินกำหนด
 	shouldBlock, err := services.CheckAndBlockIfNeeded(c, firestoreClient, req.Email, "resetpassword")
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check OTP request count"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check OTP request count"))
 		return
 	}
 	if shouldBlock {
-		c.JSON(403, gin.H{"error": "Too many OTP requests. Your email has been blocked temporarily."})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPBlocked))
 		return
 	}
 
@@ -143,29 +147,29 @@ func ResetpasswordOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	})
 }
 
-func Sendemail(c *gin.Context, firestoreClient *firestore.Client) {
+func Sendemail(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	var req dto.SendemailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// ตรวจสอบว่าอีเมลนี้มีอยู่ในระบบหรือไม่
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	exists, err := services.UserExist(ctx, firestoreClient, req.Email)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check existing email"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check existing email"))
 		return
 	}
 	if !exists {
-		c.JSON(400, gin.H{"error": "Email is not already registered"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthEmailNotRegistered))
 		return
 	}
 
 	// สร้าง OTP และ REF
 	otp, err := services.GenerateOTP(6)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to generate OTP"})
+		apperror.Abort(c, apperror.Internal(err, "failed to generate OTP"))
 		return
 	}
 
@@ -184,16 +188,17 @@ func Sendemail(c *gin.Context, firestoreClient *firestore.Client) {
 		recordemail = "รหัส OTP สำหรับรีเซ็ตรหัสผ่าน"
 		recordfirebase = "resetpassword"
 	}
-	err = services.SendingEmail(req.Email, recordemail, emailContent)
+	err = services.SendingEmail(ctx, cfg.SMTP, req.Email, recordemail, emailContent)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to send email: " + err.Error()})
+		apperror.Abort(c, apperror.Internal(err, "failed to send email"))
 		return
 	}
+	metrics.OTPSent(recordfirebase)
 
 	// บันทึกข้อมูล OTP ลงใน Firebase
 	err = services.SaveOTPRecord(c, firestoreClient, req.Email, otp, req.Reference, recordfirebase)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to save OTP record: " + err.Error()})
+		apperror.Abort(c, apperror.Internal(err, "failed to save OTP record"))
 		return
 	}
 
@@ -202,22 +207,22 @@ func Sendemail(c *gin.Context, firestoreClient *firestore.Client) {
 	})
 }
 
-func ResendOTP(c *gin.Context, firestoreClient *firestore.Client) {
+func ResendOTP(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	var req dto.ResendOTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// ตรวจสอบว่าอีเมลนี้มีอยู่ในระบบหรือไม่
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	exists, err := services.UserExist(ctx, firestoreClient, req.Email)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check existing email"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check existing email"))
 		return
 	}
 	if !exists {
-		c.JSON(400, gin.H{"error": "Email is not already registered"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthEmailNotRegistered))
 		return
 	}
 
@@ -237,29 +242,29 @@ func ResendOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	// ตรวจสอบว่าอีเมลถูกบล็อกหรือไม่
 	blocked, err := services.IsEmailBlocked(c, firestoreClient, req.Email, recordfirebase)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check email status"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check email status"))
 		return
 	}
 	if blocked {
-		c.JSON(403, gin.H{"error": "Too many OTP requests. Please try again later."})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPBlocked))
 		return
 	}
 
 	// ตรวจสอบจำนวนครั้งที่ขอ OTP และบล็อกถ้าเกินกำหนด
 	shouldBlock, err := services.CheckAndBlockIfNeeded(c, firestoreClient, req.Email, recordfirebase)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check OTP request count"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check OTP request count"))
 		return
 	}
 	if shouldBlock {
-		c.JSON(403, gin.H{"error": "Too many OTP requests. Your email has been blocked temporarily."})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPBlocked))
 		return
 	}
 
 	// สร้าง OTP และ REF ใหม่
 	otp, err := services.GenerateOTP(6)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to generate OTP"})
+		apperror.Abort(c, apperror.Internal(err, "failed to generate OTP"))
 		return
 	}
 
@@ -268,15 +273,16 @@ func ResendOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	// สร้างเนื้อหาอีเมล
 	emailContent := services.GenerateEmailContent(otp, ref)
 
-	err = services.SendingEmail(req.Email, recordemail, emailContent)
+	err = services.SendingEmail(ctx, cfg.SMTP, req.Email, recordemail, emailContent)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to send email: " + err.Error()})
+		apperror.Abort(c, apperror.Internal(err, "failed to send email"))
 		return
 	}
+	metrics.OTPSent(recordfirebase)
 
 	err = services.SaveOTPRecord(c, firestoreClient, req.Email, otp, ref, recordfirebase)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to save OTP record: " + err.Error()})
+		apperror.Abort(c, apperror.Internal(err, "failed to save OTP record"))
 		return
 	}
 
@@ -286,29 +292,29 @@ func ResendOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	})
 }
 
-func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
+func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	// รับข้อมูลจาก request
 	var verifyRequest dto.VerifyRequest
 	if err := c.ShouldBindJSON(&verifyRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// ตรวจสอบว่ามีผู้ใช้ในระบบไหม
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	exists, err := services.UserExist(ctx, firestoreClient, verifyRequest.Email)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check existing email"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check existing email"))
 		return
 	}
 	if !exists {
-		c.JSON(400, gin.H{"error": "Email is not already registered"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthEmailNotRegistered))
 		return
 	}
 
 	// ตรวจสอบว่า input ไม่เป็นค่าว่าง
 	if verifyRequest.Record == "" || verifyRequest.Reference == "" || verifyRequest.OTP == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record, Reference and OTP are required"})
+		apperror.Abort(c, apperror.Invalid("Record, Reference and OTP are required"))
 		return
 	}
 
@@ -331,10 +337,9 @@ func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
 
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Invalid reference code"})
+			apperror.Abort(c, apperror.New(apperror.CodeOTPReferenceInvalid))
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve OTP record"})
-			fmt.Printf("Firestore error: %v", err) // บันทึก error ที่เกิดขึ้นโดยไม่แสดงให้ user เห็น
+			apperror.Abort(c, apperror.Internal(err, "failed to retrieve OTP record"))
 		}
 		return
 	}
@@ -343,27 +348,26 @@ func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	var otpRecord model.OTPRecord
 
 	if err := docSnap.DataTo(&otpRecord); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse OTP record"})
-		fmt.Printf("Data parsing error: %v", err)
+		apperror.Abort(c, apperror.Internal(err, "failed to parse OTP record"))
 		return
 	}
 
 	// ตรวจสอบว่า OTP ถูกใช้ไปแล้วหรือไม่
 	if otpRecord.Is_used == "1" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP has already been used"})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPAlreadyUsed))
 		return
 	}
 
 	// ตรวจสอบว่า OTP หมดอายุหรือยัง
 	currentTime := time.Now()
 	if currentTime.After(otpRecord.ExpiresAt) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP has expired"})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPExpired))
 		return
 	}
 
 	// ตรวจสอบว่า OTP ตรงกันหรือไม่
 	if otpRecord.OTP != verifyRequest.OTP {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OTP"})
+		apperror.Abort(c, apperror.New(apperror.CodeOTPInvalid))
 		return
 	}
 
@@ -373,8 +377,7 @@ func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	})
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update OTP status"})
-		fmt.Printf("Firestore update error: %v", err)
+		apperror.Abort(c, apperror.Internal(err, "failed to update OTP status"))
 		return
 	}
 
@@ -387,48 +390,35 @@ func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	if recordfirebase == "verify" {
 		docRef, err = services.GetUserExist(ctx, firestoreClient, verifyRequest.Email)
 		if err != nil {
-			c.JSON(404, gin.H{"error": err.Error()})
+			apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 			return
 		}
 
 		// อัปเดทฟิลด์ verify
 		_, err = docRef.Update(ctx, []firestore.Update{
-			{Path: "verify", Value: "1"},
+			{Path: "verify", Value: model.Verified},
 		})
 		if err != nil {
-			c.JSON(500, gin.H{"error": "Failed to update verify field"})
+			apperror.Abort(c, apperror.Internal(err, "failed to update verify field"))
 			return
 		}
 
 		docSnap, err := services.GetUserData(ctx, firestoreClient, verifyRequest.Email)
 		if err != nil {
-			c.JSON(404, gin.H{"error": err.Error()})
+			apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 			return
 		}
 		// แปลงข้อมูลเป็น struct
 		var user model.User
 		if err := docSnap.DataTo(&user); err != nil {
-			c.JSON(500, gin.H{"error": "Failed to parse user data"})
+			apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 			return
 		}
 
 		// สร้าง tokens
-		accessToken, err := services.CreateAccessToken(user.UserID, user.Role)
+		accessToken, refreshToken, hashedRefreshToken, err := issueTokens(ctx, cfg, &user)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create access token"})
-			return
-		}
-
-		refreshToken, err := services.CreateRefreshToken(user.UserID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create refresh token"})
-			return
-		}
-
-		// แฮช refresh token
-		hashedRefreshToken, err := services.HashRefreshToken(refreshToken)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash refresh token"})
+			apperror.Abort(c, apperror.Internal(err, "failed to create tokens"))
 			return
 		}
 
@@ -448,7 +438,7 @@ func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
 
 		// บันทึก refresh token ใน Firestore
 		if _, err := firestoreClient.Collection("refreshTokens").Doc(user.UserID).Set(ctx, refreshTokenData); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store refresh token"})
+			apperror.Abort(c, apperror.Internal(err, "failed to store refresh token"))
 			return
 		}
 
@@ -461,56 +451,74 @@ func VerifyOTP(c *gin.Context, firestoreClient *firestore.Client) {
 	c.JSON(http.StatusOK, responseData)
 }
 
-func NewAccessToken(c *gin.Context, firestoreClient *firestore.Client) {
+// issueTokens สร้าง access token, refresh token และ hash ของ refresh token ที่จะเก็บใน Firestore
+// แยกเป็น span ของตัวเองเพราะ bcrypt ใช้เวลาพอสมควร
+func issueTokens(ctx context.Context, cfg *config.Config, user *model.User) (accessToken, refreshToken, hashedRefreshToken string, err error) {
+	_, span := tracing.Start(ctx, "auth.IssueTokens")
+	defer func() { tracing.End(span, err) }()
+
+	if accessToken, err = services.CreateAccessToken(cfg.JWT, user.UserID, user.Role); err != nil {
+		return "", "", "", fmt.Errorf("failed to create access token: %w", err)
+	}
+	if refreshToken, err = services.CreateRefreshToken(cfg.JWT, user.UserID); err != nil {
+		return "", "", "", fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	if hashedRefreshToken, err = services.HashRefreshToken(refreshToken); err != nil {
+		return "", "", "", fmt.Errorf("failed to hash refresh token: %w", err)
+	}
+	return accessToken, refreshToken, hashedRefreshToken, nil
+}
+
+func NewAccessToken(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	userId := c.MustGet("userID").(string)
 	refreshToken := c.MustGet("refreshToken").(string)
 	docRef := firestoreClient.Collection("refreshTokens").Doc(userId)
 	docSnap, err := docRef.Get(c)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to get refresh token from database"})
+		apperror.Abort(c, apperror.Internal(err, "failed to get refresh token from database"))
 		return
 	}
 	var tokenData model.TokenResponse
 	if err := docSnap.DataTo(&tokenData); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to parse token data"})
+		apperror.Abort(c, apperror.Internal(err, "failed to parse token data"))
 		return
 	}
 	// ตรวจสอบว่า token ถูก revoke หรือไม่
 	if tokenData.Revoked {
-		c.JSON(403, gin.H{"error": "Token has been revoked"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthRefreshRevoked))
 		return
 	}
 
 	// ตรวจสอบว่า token หมดอายุหรือไม่ (เช็คอีกครั้งจากฐานข้อมูล)
 	if tokenData.CreatedAt+tokenData.ExpiresIn < time.Now().Unix() {
-		c.JSON(401, gin.H{"error": "Token has expired"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthRefreshExpired))
 		return
 	}
 	// ตรวจสอบ token ที่ส่งมากับ hash ที่เก็บไว้
 	hash := sha256.Sum256([]byte(refreshToken))
 	if err := bcrypt.CompareHashAndPassword([]byte(tokenData.RefreshToken), hash[:]); err != nil {
-		c.JSON(401, gin.H{"error": "Invalid refresh token"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthRefreshInvalid))
 		return
 	}
 
 	// ถ้าผ่านการตรวจสอบทั้งหมด ดึงข้อมูล user
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	docSnap, err = services.GetUserDataByUserid(ctx, firestoreClient, userId)
 	if err != nil {
-		c.JSON(404, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 		return
 	}
 	// แปลงข้อมูลเป็น struct
 	var user model.User
 	if err := docSnap.DataTo(&user); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to parse user data"})
+		apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 		return
 	}
 
 	// สร้าง access token ใหม่
-	newAccessToken, err := services.CreateAccessToken(user.UserID, user.Role)
+	newAccessToken, err := services.CreateAccessToken(cfg.JWT, user.UserID, user.Role)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create new access token"})
+		apperror.Abort(c, apperror.Internal(err, "failed to create new access token"))
 		return
 	}
 	c.JSON(200, gin.H{"accessToken": newAccessToken})
@@ -519,28 +527,28 @@ func NewAccessToken(c *gin.Context, firestoreClient *firestore.Client) {
 func ResetPassword(c *gin.Context, firestoreClient *firestore.Client) {
 	var resetPassword dto.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&resetPassword); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	docSnap, err := services.GetUserData(ctx, firestoreClient, resetPassword.Email)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 		return
 	}
 
 	// แปลงข้อมูลเป็น struct
 	var user model.User
 	if err := docSnap.DataTo(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse user data"})
+		apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 		return
 	}
 
 	// hash password ใหม่
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(resetPassword.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		apperror.Abort(c, apperror.Internal(err, "failed to hash password"))
 		return
 	}
 
@@ -557,7 +565,7 @@ func ResetPassword(c *gin.Context, firestoreClient *firestore.Client) {
 			},
 		})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password in Firestore"})
+		apperror.Abort(c, apperror.Internal(err, "failed to update password in Firestore"))
 		return
 	}
 
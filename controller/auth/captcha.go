@@ -3,16 +3,21 @@ package auth
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"myapp/apperror"
+	"myapp/config"
 	"myapp/dto"
-
-	"os"
+	"myapp/tracing"
 	"strings"
 
 	"cloud.google.com/go/firestore"
 	recaptcha "cloud.google.com/go/recaptchaenterprise/v2/apiv1"
 	"cloud.google.com/go/recaptchaenterprise/v2/apiv1/recaptchaenterprisepb"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
 )
 
 // ResponseData โครงสร้างข้อมูลสำหรับส่งกลับ
@@ -24,31 +29,25 @@ type ResponseData struct {
 	Message string   `json:"message,omitempty"`
 }
 
-func CaptchaController(router *gin.Engine, firestoreClient *firestore.Client) {
+func CaptchaController(router gin.IRouter, firestoreClient *firestore.Client, cfg *config.Config) {
 	routes := router.Group("/auth")
 	{
 		routes.POST("/captcha", func(c *gin.Context) {
-			VerifyCaptcha(c, firestoreClient)
+			VerifyCaptcha(c, firestoreClient, cfg)
 		})
 	}
 }
 
-func VerifyCaptcha(c *gin.Context, firestoreClient *firestore.Client) {
+func VerifyCaptcha(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	var req dto.CaptchaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{
-			"success": false,
-			"message": "Invalid request format",
-		})
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
 		return
 	}
 
 	// ตรวจสอบข้อมูลที่จำเป็น
 	if req.Token == "" {
-		c.JSON(400, gin.H{
-			"success": false,
-			"message": "Token is required",
-		})
+		apperror.Abort(c, apperror.Invalid("token is required"))
 		return
 	}
 
@@ -56,28 +55,16 @@ func VerifyCaptcha(c *gin.Context, firestoreClient *firestore.Client) {
 	userIPAddress := getClientIP(c)
 	userAgent := c.Request.UserAgent()
 
-	// ดึงค่า env แบบรวมครั้งเดียว
-	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT_ID")
-	recaptchaKey := os.Getenv("RECAPTCHA_SITE_KEY")
-	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_2")
-
 	// เรียกใช้ createAssessment เพื่อตรวจสอบ reCAPTCHA
-	result, err := createAssessment(c.Request.Context(), projectID, recaptchaKey, credentialsPath, req.Token, req.Action, userIPAddress, userAgent)
+	result, err := createAssessment(c.Request.Context(), cfg.Captcha.ProjectID, cfg.Captcha.SiteKey, cfg.Captcha.CredentialsFile, req.Token, req.Action, userIPAddress, userAgent)
 
 	if err != nil {
-		fmt.Printf("❌ Error verifying reCAPTCHA: %v\n", err)
-		c.JSON(500, gin.H{
-			"success": false,
-			"message": "Internal server error",
-		})
+		apperror.Abort(c, apperror.Internal(err, "failed to verify recaptcha"))
 		return
 	}
 
 	if result == nil {
-		c.JSON(400, gin.H{
-			"success": false,
-			"message": "reCAPTCHA verification failed",
-		})
+		apperror.Abort(c, apperror.New(apperror.CodeCaptchaFailed))
 		return
 	}
 
@@ -104,12 +91,17 @@ func getClientIP(c *gin.Context) string {
 	return userIPAddress
 }
 
-func createAssessment(ctx context.Context, projectID, recaptchaKey, credentialsPath, token, action, userIPAddress, userAgent string) (*dto.AssessmentResult, error) {
+func createAssessment(ctx context.Context, projectID, recaptchaKey, credentialsPath, token, action, userIPAddress, userAgent string) (_ *dto.AssessmentResult, err error) {
+	ctx, span := tracing.Start(ctx, "recaptcha.CreateAssessment", attribute.String("recaptcha.action", action))
+	defer func() { tracing.End(span, err) }()
+
 	// สร้าง reCAPTCHA client โดยระบุไฟล์ credentials
-	client, err := recaptcha.NewClient(ctx, option.WithCredentialsFile(credentialsPath))
+	client, err := recaptcha.NewClient(ctx,
+		option.WithCredentialsFile(credentialsPath),
+		option.WithGRPCDialOption(grpc.WithStatsHandler(otelgrpc.NewClientHandler())),
+	)
 	if err != nil {
-		fmt.Printf("❌ Error creating reCAPTCHA client: %v\n", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to create recaptcha client: %w", err)
 	}
 	defer client.Close()
 
@@ -130,24 +122,22 @@ func createAssessment(ctx context.Context, projectID, recaptchaKey, credentialsP
 	// เรียก API
 	response, err := client.CreateAssessment(ctx, req)
 	if err != nil {
-		fmt.Printf("❌ Error in createAssessment: %v\n", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to create assessment: %w", err)
 	}
 
 	// ตรวจสอบความถูกต้องของ token
 	if response.TokenProperties == nil || !response.TokenProperties.Valid {
 		if response.TokenProperties != nil {
-			fmt.Printf("❌ Token invalid: %s\n", response.TokenProperties.InvalidReason)
+			slog.WarnContext(ctx, "recaptcha token invalid", "reason", response.TokenProperties.InvalidReason.String())
 		} else {
-			fmt.Println("❌ Token properties are null or undefined.")
+			slog.WarnContext(ctx, "recaptcha token properties are missing")
 		}
 		return nil, nil
 	}
 
 	// ตรวจสอบ action ถ้ามีการระบุ
 	if action != "" && response.TokenProperties.Action != action {
-		fmt.Printf("⚠️ Action mismatch: expected %s, but got %s\n",
-			action, response.TokenProperties.Action)
+		slog.WarnContext(ctx, "recaptcha action mismatch", "expected", action, "actual", response.TokenProperties.Action)
 		return nil, nil
 	}
 
@@ -1,8 +1,10 @@
 package auth
 
 import (
-	"context"
+	"myapp/apperror"
+	"myapp/config"
 	"myapp/dto"
+	"myapp/metrics"
 	"myapp/model"
 	"myapp/services"
 	"net/http"
@@ -13,43 +15,33 @@ import (
 	"github.com/google/uuid"
 )
 
-func GoogleSignInController(router *gin.Engine, firestoreClient *firestore.Client) {
-	router.POST("/auth/googlelogin", func(c *gin.Context) {
-		GoogleSignIn(c, firestoreClient)
+func GoogleSignInController(router gin.IRouter, firestoreClient *firestore.Client, cfg *config.Config) {
+	router.POST("/auth/google", func(c *gin.Context) {
+		GoogleSignIn(c, firestoreClient, cfg)
 	})
 }
 
-func GoogleSignIn(c *gin.Context, firestoreClient *firestore.Client) {
+func GoogleSignIn(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	// รับและตรวจสอบข้อมูลจาก Request
 	var req dto.GoogleSignInRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "กรุณาระบุข้อมูลให้ครบถ้วนและถูกต้อง",
-		})
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
 		return
 	}
 
 	// ตรวจสอบข้อมูลที่จำเป็น
 	if req.Email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "กรุณาระบุอีเมล",
-		})
+		apperror.Abort(c, apperror.Invalid("email is required"))
 		return
 	}
 
 	// ค้นหาผู้ใช้จากฐานข้อมูล
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	usersCollection := firestoreClient.Collection("Users")
 	query := usersCollection.Where("email", "==", req.Email).Limit(1)
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "เกิดข้อผิดพลาดในการค้นหาผู้ใช้",
-			"error":   err.Error(),
-		})
+		apperror.Abort(c, apperror.Internal(err, "failed to find user"))
 		return
 	}
 
@@ -60,43 +52,33 @@ func GoogleSignIn(c *gin.Context, firestoreClient *firestore.Client) {
 	if len(docs) > 0 {
 		// ✅ เจอผู้ใช้
 		if err := docs[0].DataTo(&user); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "ไม่สามารถอ่านข้อมูลผู้ใช้ได้",
-				"error":   err.Error(),
-			})
+			apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 			return
 		}
 
 		// อัพเดท verify status ใน Users collection (ไม่ใช่ OTPRecords)
-		if user.Verify == "0" {
+		if user.Verify == model.VerifyPending {
 			userDocRef := firestoreClient.Collection("Users").Doc(user.UserID)
 			_, err = userDocRef.Update(ctx, []firestore.Update{
-				{Path: "verify", Value: "1"},
+				{Path: "verify", Value: model.Verified},
 			})
 			if err != nil {
 				// Log error แต่ไม่หยุดการทำงาน
 				// สามารถใช้ logger ที่เหมาะสมได้
 			} else {
-				user.Verify = "1" // อัพเดทค่าใน memory
+				user.Verify = model.Verified // อัพเดทค่าใน memory
 			}
 		}
 
 		// ตรวจสอบสถานะบัญชี
 		switch user.Active {
-		case "0":
-			c.JSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"message": "บัญชีผู้ใช้ไม่ได้เปิดใช้งาน",
-				"status":  "0",
-			})
+		case model.AccountInactive:
+			metrics.SignIn(metrics.SignInGoogle, metrics.ResultFailure, "inactive")
+			apperror.Abort(c, apperror.New(apperror.CodeAuthAccountInactive).WithField("status", model.AccountInactive))
 			return
-		case "2":
-			c.JSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"message": "บัญชีผู้ใช้ถูกลบแล้ว",
-				"status":  "2",
-			})
+		case model.AccountDeleted:
+			metrics.SignIn(metrics.SignInGoogle, metrics.ResultFailure, "deleted")
+			apperror.Abort(c, apperror.New(apperror.CodeAuthAccountDeleted).WithField("status", model.AccountDeleted))
 			return
 		}
 
@@ -114,52 +96,36 @@ func GoogleSignIn(c *gin.Context, firestoreClient *firestore.Client) {
 			Password:  "-",
 			Profile:   "none-url",
 			Role:      role,
-			Verify:    "1", // Google user ถือว่า verified แล้ว
-			Active:    "1",
+			Verify:    model.Verified, // Google user ถือว่า verified แล้ว
+			Active:    model.AccountActive,
 			CreatedAt: time.Now(),
 		}
 
 		_, err = firestoreClient.Collection("Users").Doc(docid).Set(ctx, user)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "ไม่สามารถสร้างบัญชีผู้ใช้ได้",
-				"error":   err.Error(),
-			})
+			apperror.Abort(c, apperror.Internal(err, "failed to create user"))
 			return
 		}
 		isNewUser = true
 	}
 
 	// สร้าง tokens
-	accessToken, err := services.CreateAccessToken(user.UserID, user.Role)
+	accessToken, err := services.CreateAccessToken(cfg.JWT, user.UserID, user.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "ไม่สามารถสร้าง access token ได้",
-			"error":   err.Error(),
-		})
+		apperror.Abort(c, apperror.Internal(err, "failed to create access token"))
 		return
 	}
 
-	refreshToken, err := services.CreateRefreshToken(user.UserID)
+	refreshToken, err := services.CreateRefreshToken(cfg.JWT, user.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "ไม่สามารถสร้าง refresh token ได้",
-			"error":   err.Error(),
-		})
+		apperror.Abort(c, apperror.Internal(err, "failed to create refresh token"))
 		return
 	}
 
 	// แฮช refresh token
 	hashedRefreshToken, err := services.HashRefreshToken(refreshToken)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "ไม่สามารถสร้าง hashed token ได้",
-			"error":   err.Error(),
-		})
+		apperror.Abort(c, apperror.Internal(err, "failed to hash refresh token"))
 		return
 	}
 
@@ -179,11 +145,7 @@ func GoogleSignIn(c *gin.Context, firestoreClient *firestore.Client) {
 
 	// บันทึก refresh token ใน Firestore
 	if _, err := firestoreClient.Collection("refreshTokens").Doc(user.UserID).Set(ctx, refreshTokenData); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "ไม่สามารถบันทึก refresh token ได้",
-			"error":   err.Error(),
-		})
+		apperror.Abort(c, apperror.Internal(err, "failed to store refresh token"))
 		return
 	}
 
@@ -193,6 +155,7 @@ func GoogleSignIn(c *gin.Context, firestoreClient *firestore.Client) {
 		message = "สร้างบัญชีและเข้าสู่ระบบสำเร็จ"
 	}
 
+	metrics.SignIn(metrics.SignInGoogle, metrics.ResultSuccess, "")
 	// ส่งผลลัพธ์กลับ
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
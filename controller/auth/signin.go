@@ -1,10 +1,15 @@
 package auth
 
 import (
-	"context"
+	"errors"
+	"myapp/apperror"
+	"myapp/config"
 	"myapp/dto"
+	"myapp/metrics"
+	"myapp/middleware"
 	"myapp/model"
 	"myapp/services"
+	"myapp/tracing"
 	"net/http"
 	"time"
 
@@ -13,78 +18,77 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func SignInController(router *gin.Engine, firestoreClient *firestore.Client) {
-	router.POST("/auth/signin", func(c *gin.Context) {
-		Signin(c, firestoreClient)
+func SignInController(router gin.IRouter, firestoreClient *firestore.Client, cfg *config.Config) {
+	router.POST("/auth/signin", middleware.RateLimit(config.RateLimitAccount, middleware.ByEmail), func(c *gin.Context) {
+		Signin(c, firestoreClient, cfg)
 	})
 }
 
-func Signin(c *gin.Context, firestoreClient *firestore.Client) {
+func Signin(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
 	var request dto.SigninRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
 		return
 	}
 
 	// ตรวจสอบข้อมูลที่จำเป็น
 	if request.Email == "" || request.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email and password are required"})
+		apperror.Abort(c, apperror.Invalid("Email and password are required"))
 		return
 	}
 
 	// ค้นหาผู้ใช้จากฐานข้อมูล
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	docSnap, err := services.GetUserData(ctx, firestoreClient, request.Email)
 	if err != nil {
-		c.JSON(404, gin.H{"error": err.Error()})
+		reason := "internal"
+		if errors.Is(err, services.ErrUserNotFound) {
+			reason = "user_not_found"
+		}
+		metrics.SignIn(metrics.SignInPassword, metrics.ResultFailure, reason)
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 		return
 	}
 	// แปลงข้อมูลเป็น struct
 	var user model.User
 	if err := docSnap.DataTo(&user); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to parse user data"})
+		apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 		return
 	}
 
 	// ตรวจสอบรหัสผ่าน
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+	_, span := tracing.Start(ctx, "auth.ComparePassword")
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
+	span.End()
+	if err != nil {
+		metrics.SignIn(metrics.SignInPassword, metrics.ResultFailure, "invalid_password")
+		apperror.Abort(c, apperror.New(apperror.CodeAuthInvalidPassword))
 		return
 	}
 
 	// ตรวจสอบสถานะบัญชีผู้ใช้
 	switch user.Active {
-	case "0":
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User account is not active", "status": "0"})
+	case model.AccountInactive:
+		metrics.SignIn(metrics.SignInPassword, metrics.ResultFailure, "inactive")
+		apperror.Abort(c, apperror.New(apperror.CodeAuthAccountInactive).WithField("status", model.AccountInactive))
 		return
-	case "2":
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User account is deleted", "status": "2"})
+	case model.AccountDeleted:
+		metrics.SignIn(metrics.SignInPassword, metrics.ResultFailure, "deleted")
+		apperror.Abort(c, apperror.New(apperror.CodeAuthAccountDeleted).WithField("status", model.AccountDeleted))
 		return
 	}
 
 	// ตรวจสอบการยืนยันบัญชี
-	if user.Verify != "1" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User account is not verified"})
+	if user.Verify != model.Verified {
+		metrics.SignIn(metrics.SignInPassword, metrics.ResultFailure, "unverified")
+		apperror.Abort(c, apperror.New(apperror.CodeAuthAccountUnverified))
 		return
 	}
 
 	// สร้าง tokens
-	accessToken, err := services.CreateAccessToken(user.UserID, user.Role)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create access token"})
-		return
-	}
-
-	refreshToken, err := services.CreateRefreshToken(user.UserID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create refresh token"})
-		return
-	}
-
-	// แฮช refresh token
-	hashedRefreshToken, err := services.HashRefreshToken(refreshToken)
+	accessToken, refreshToken, hashedRefreshToken, err := issueTokens(ctx, cfg, &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash refresh token"})
+		apperror.Abort(c, apperror.Internal(err, "failed to create tokens"))
 		return
 	}
 
@@ -104,7 +108,7 @@ func Signin(c *gin.Context, firestoreClient *firestore.Client) {
 
 	// บันทึก refresh token ใน Firestore
 	if _, err := firestoreClient.Collection("refreshTokens").Doc(user.UserID).Set(c, refreshTokenData); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store refresh token ", "detail": err.Error()})
+		apperror.Abort(c, apperror.Internal(err, "failed to store refresh token"))
 		return
 	}
 
@@ -125,10 +129,11 @@ func Signin(c *gin.Context, firestoreClient *firestore.Client) {
 
 	// บันทึกข้อมูลการเข้าสู่ระบบใน Firestore
 	if _, err := firestoreClient.Collection("Users").Doc(user.UserID).Set(c, loginData, firestore.MergeAll); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update login status"})
+		apperror.Abort(c, apperror.Internal(err, "failed to update login status"))
 		return
 	}
 
+	metrics.SignIn(metrics.SignInPassword, metrics.ResultSuccess, "")
 	// ส่งผลลัพธ์กลับ
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login Successfully",
@@ -1,9 +1,10 @@
 package auth
 
 import (
-	"context"
-	"errors"
+	"myapp/apperror"
+	"myapp/config"
 	"myapp/dto"
+	"myapp/middleware"
 	"myapp/model"
 	"myapp/services"
 	"net"
@@ -18,14 +19,15 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func SignUpController(router *gin.Engine, firestoreClient *firestore.Client) {
-	router.POST("/auth/signup", func(c *gin.Context) {
+func SignUpController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.POST("/auth/signup", middleware.RateLimit(config.RateLimitAccount, middleware.ByEmail), func(c *gin.Context) {
 		Signup(c, firestoreClient)
 	})
 }
 
-func SignUpGetEmailController(router *gin.Engine, firestoreClient *firestore.Client) {
-	router.POST("/email", func(c *gin.Context) {
+func SignUpGetEmailController(router gin.IRouter, firestoreClient *firestore.Client) {
+	// จำกัดต่ออีเมลนอกเหนือจากต่อ IP ของกลุ่ม /auth เพื่อไม่ให้ไล่ตรวจว่าอีเมลไหนมีบัญชี
+	router.POST("/auth/lookup", middleware.RateLimit(config.RateLimitAccount, middleware.ByEmail), func(c *gin.Context) {
 		GetEmail(c, firestoreClient)
 	})
 }
@@ -33,28 +35,28 @@ func SignUpGetEmailController(router *gin.Engine, firestoreClient *firestore.Cli
 func Signup(c *gin.Context, firestoreClient *firestore.Client) {
 	var request dto.SignupRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
 		return
 	}
 	if err := isValidEmail(request.Email); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+		apperror.Abort(c, err)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	exists, err := services.UserExist(ctx, firestoreClient, request.Email)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check existing email"})
+		apperror.Abort(c, apperror.Internal(err, "failed to check existing email"))
 		return
 	}
 	if exists {
-		c.JSON(400, gin.H{"error": "Email is already registered"})
+		apperror.Abort(c, apperror.New(apperror.CodeAuthEmailTaken))
 		return
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to hash password"})
+		apperror.Abort(c, apperror.Internal(err, "failed to hash password"))
 		return
 	}
 
@@ -67,15 +69,15 @@ func Signup(c *gin.Context, firestoreClient *firestore.Client) {
 		Password:  string(hashedPassword),
 		Profile:   "none-url",
 		Role:      "user",
-		Verify:    "0",
-		Active:    "1",
+		Verify:    model.VerifyPending,
+		Active:    model.AccountActive,
 		CreatedAt: time.Now(),
 	}
 
 	// ประกาศ docRef ก่อนใช้
 	_, err = firestoreClient.Collection("Users").Doc(docid).Set(ctx, newUser)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create user"})
+		apperror.Abort(c, apperror.Internal(err, "failed to create user"))
 		return
 	}
 
@@ -90,20 +92,20 @@ func isValidEmail(email string) error {
 	const emailRegex = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	re := regexp.MustCompile(emailRegex)
 	if !re.MatchString(email) {
-		return errors.New("invalid email format")
+		return apperror.Invalid("invalid email format")
 	}
 
 	// Extract domain from email
 	parts := strings.Split(email, "@")
 	if len(parts) != 2 {
-		return errors.New("invalid email structure")
+		return apperror.Invalid("invalid email structure")
 	}
 	domain := parts[1]
 
 	// Check for MX records
 	mxRecords, err := net.LookupMX(domain)
 	if err != nil || len(mxRecords) == 0 {
-		return errors.New("email domain does not have valid MX records")
+		return apperror.Invalid("email domain does not have valid MX records")
 	}
 
 	return nil
@@ -112,21 +114,21 @@ func isValidEmail(email string) error {
 func GetEmail(c *gin.Context, firestoreClient *firestore.Client) {
 	var emailReq dto.EmailRequest
 	if err := c.ShouldBindJSON(&emailReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// Query ข้อมูลจากฐานข้อมูล
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	docSnap, err := services.GetUserData(ctx, firestoreClient, emailReq.Email)
 	if err != nil {
-		c.JSON(404, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 		return
 	}
 	// แปลงข้อมูลเป็น struct
 	var user model.User
 	if err := docSnap.DataTo(&user); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to parse user data"})
+		apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 		return
 	}
 
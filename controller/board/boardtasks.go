@@ -0,0 +1,105 @@
+package board
+
+import (
+	"fmt"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/pagination"
+	"myapp/services"
+	"net/http"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+func BoardTaskController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.GET("/boards/:boardid/tasks", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		ListBoardTasks(c, firestoreClient)
+	})
+}
+
+// ListBoardTasks รายการ task ในบอร์ดแบ่งหน้าเรียงตาม position คอลัมน์ของบอร์ดดูได้จาก /boards/:boardid/columns
+// กรองด้วย ?status=<columnid> เพื่อดึงทีละคอลัมน์ และ ?label=<labelid> (ส่งได้หลายค่า ตรงค่าใดค่าหนึ่ง)
+func ListBoardTasks(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+
+	page, err := pagination.FromQuery(c, "board:"+boardId, pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	board, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId)
+	if err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	// ต้องมี composite index: boardid (+ status) (+ labels) + position + __name__
+	query := firestoreClient.Collection("Tasks").Where("boardid", "==", boardId)
+	if value := c.Query("status"); value != "" {
+		status := model.TaskStatus(value)
+		if _, _, ok := services.FindColumn(services.BoardColumns(board), status); !ok {
+			apperror.Abort(c, apperror.Invalid(fmt.Sprintf("unknown columnid %q", value)))
+			return
+		}
+		query = query.Where("status", "==", status)
+	}
+	if labelIDs := services.ParseIDList(c.QueryArray("label")); len(labelIDs) > 0 {
+		if len(labelIDs) > services.MaxLabelFilter {
+			apperror.Abort(c, apperror.Invalid(fmt.Sprintf("at most %d labels can be filtered at once", services.MaxLabelFilter)))
+			return
+		}
+		query = query.Where("labels", "array-contains-any", labelIDs)
+	}
+	query = query.OrderBy("position", firestore.Asc).OrderBy(firestore.DocumentID, firestore.Asc)
+
+	docs, err := page.Query(query).Documents(ctx).GetAll()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+		return
+	}
+	tasks := make([]model.Tasks, 0, len(docs))
+	for _, doc := range docs {
+		var task model.Tasks
+		if err := doc.DataTo(&task); err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+			return
+		}
+		tasks = append(tasks, task)
+	}
+	tasks, next, err := pagination.Next(page, tasks, func(task model.Tasks) []interface{} {
+		return []interface{}{task.Position, task.TaskID}
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+		return
+	}
+
+	items := make([]dto.TaskSummary, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, dto.TaskSummary{
+			TaskID:    task.TaskID,
+			BoardID:   task.BoardID,
+			TaskName:  task.TaskName,
+			Status:    task.Status,
+			Priority:  task.Priority,
+			DueDate:   services.FormatTaskTime(task.DueDate, task.AllDay, loc),
+			AllDay:    task.AllDay,
+			Position:  task.Position,
+			Labels:    dto.EmptyIfNil(task.Labels),
+			Assignees: dto.EmptyIfNil(task.Assignees),
+		})
+	}
+	c.JSON(http.StatusOK, dto.NewPage(items, next))
+}
@@ -0,0 +1,216 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func ColumnController(router gin.IRouter, firestoreClient *firestore.Client) {
+	routes := router.Group("/boards/:boardid/columns", middleware.AccessTokenMiddleware())
+	{
+		routes.GET("", func(c *gin.Context) {
+			GetColumns(c, firestoreClient)
+		})
+		routes.PUT("", func(c *gin.Context) {
+			UpdateColumns(c, firestoreClient)
+		})
+	}
+}
+
+func GetColumns(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+
+	board, err := services.GetBoardForUser(c.Request.Context(), firestoreClient, boardId, userId)
+	if err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"columns": toColumnResponses(services.BoardColumns(board))})
+}
+
+// UpdateColumns แทนที่คอลัมน์ทั้งหมดของบอร์ดตามลำดับที่ส่งมา (เฉพาะเจ้าของบอร์ด)
+// - columnid ว่างคือคอลัมน์ใหม่
+// - คอลัมน์ที่ไม่ได้ส่งมาจะถูกลบ ต้องไม่มี task อยู่
+// - เมื่อเปลี่ยนค่า done จะปรับ completedat ของ task ในคอลัมน์นั้นด้วย
+func UpdateColumns(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+
+	var req dto.UpdateColumnsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+	if len(req.Columns) == 0 || len(req.Columns) > services.MaxBoardColumns {
+		apperror.Abort(c, apperror.Invalid("columns must contain between 1 and 20 items"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	board, err := services.GetBoard(ctx, firestoreClient, boardId)
+	if err != nil {
+		respondBoardError(c, err)
+		return
+	}
+	if board.CreatedBy != userId {
+		apperror.Abort(c, apperror.New(apperror.CodeBoardOwnerOnly))
+		return
+	}
+
+	boardRef := firestoreClient.Collection("Boards").Doc(boardId)
+	var columns []model.BoardColumn
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(boardRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return services.ErrBoardNotFound
+			}
+			return err
+		}
+		var current model.Board
+		if err := docSnap.DataTo(&current); err != nil {
+			return err
+		}
+
+		columns, err = buildColumns(services.BoardColumns(&current), req.Columns)
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[model.TaskStatus]model.BoardColumn)
+		for _, column := range services.BoardColumns(&current) {
+			existing[column.ColumnID] = column
+		}
+		kept := make(map[model.TaskStatus]model.BoardColumn)
+		for _, column := range columns {
+			kept[column.ColumnID] = column
+		}
+
+		// อ่านข้อมูลทั้งหมดก่อนเขียน
+		var completionChanges []*firestore.DocumentSnapshot
+		for id, old := range existing {
+			column, ok := kept[id]
+			if ok && column.Done == old.Done {
+				continue
+			}
+			docs, err := tx.Documents(firestoreClient.Collection("Tasks").
+				Where("boardid", "==", boardId).
+				Where("status", "==", id)).GetAll()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				if len(docs) > 0 {
+					return apperror.New(apperror.CodeColumnNotEmpty).WithField("column", old.Name)
+				}
+				continue
+			}
+			completionChanges = append(completionChanges, docs...)
+		}
+
+		// Firestore เขียนได้ไม่เกิน 500 รายการต่อ transaction
+		if len(completionChanges)+1 > 500 {
+			return apperror.Invalid("too many tasks are affected by the done change, please move some tasks first")
+		}
+
+		now := time.Now()
+		for _, doc := range completionChanges {
+			var task model.Tasks
+			if err := doc.DataTo(&task); err != nil {
+				return err
+			}
+			completedAt := services.CompletionTime(services.IsTaskCompleted(&task), kept[task.Status].Done, task.CompletedAt, now)
+			if err := tx.Update(doc.Ref, []firestore.Update{
+				completedAtUpdate(completedAt),
+				{Path: "updatedat", Value: now},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return tx.Update(boardRef, []firestore.Update{
+			{Path: "columns", Value: columns},
+			{Path: "updatedat", Value: now},
+		})
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to update columns"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Columns updated successfully",
+		"columns": toColumnResponses(columns),
+	})
+}
+
+// buildColumns ตรวจสอบคอลัมน์ที่ส่งมาและสร้าง id ให้คอลัมน์ใหม่
+func buildColumns(current []model.BoardColumn, requested []dto.BoardColumn) ([]model.BoardColumn, error) {
+	known := make(map[model.TaskStatus]bool, len(current))
+	for _, column := range current {
+		known[column.ColumnID] = true
+	}
+
+	columns := make([]model.BoardColumn, 0, len(requested))
+	seenIDs := make(map[model.TaskStatus]bool)
+	seenNames := make(map[string]bool)
+	for _, item := range requested {
+		name := strings.TrimSpace(item.Name)
+		if name == "" || len([]rune(name)) > 50 {
+			return nil, apperror.Invalid("column name must be between 1 and 50 characters")
+		}
+		if seenNames[strings.ToLower(name)] {
+			return nil, apperror.Invalid(fmt.Sprintf("duplicate column name %q", name))
+		}
+		seenNames[strings.ToLower(name)] = true
+
+		id := item.ColumnID
+		switch {
+		case id == "":
+			id = model.TaskStatus(uuid.New().String())
+		case !known[id]:
+			return nil, apperror.Invalid(fmt.Sprintf("unknown columnid %q", id))
+		case seenIDs[id]:
+			return nil, apperror.Invalid(fmt.Sprintf("duplicate columnid %q", id))
+		}
+		seenIDs[id] = true
+
+		columns = append(columns, model.BoardColumn{ColumnID: id, Name: name, Done: item.Done})
+	}
+	return columns, nil
+}
+
+func completedAtUpdate(t *time.Time) firestore.Update {
+	if t == nil {
+		return firestore.Update{Path: "completedat", Value: firestore.Delete}
+	}
+	return firestore.Update{Path: "completedat", Value: *t}
+}
+
+func toColumnResponses(columns []model.BoardColumn) []dto.BoardColumn {
+	response := make([]dto.BoardColumn, 0, len(columns))
+	for _, column := range columns {
+		response = append(response, dto.BoardColumn{
+			ColumnID: column.ColumnID,
+			Name:     column.Name,
+			Done:     column.Done,
+		})
+	}
+	return response
+}
@@ -1,11 +1,12 @@
 package board
 
 import (
-	"context"
 	"encoding/base64"
+	"myapp/apperror"
 	"myapp/dto"
 	"myapp/middleware"
 	"myapp/model"
+	"myapp/search"
 	"myapp/services"
 	"net/http"
 	"net/url"
@@ -17,43 +18,35 @@ import (
 	"github.com/google/uuid"
 )
 
-func CreateBoardController(router *gin.Engine, firestoreClient *firestore.Client) {
-	router.POST("/board", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
-		CreateBoard(c, firestoreClient)
+func CreateBoardController(router gin.IRouter, firestoreClient *firestore.Client, index search.Index) {
+	router.POST("/boards", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		CreateBoard(c, firestoreClient, index)
 	})
 }
 
-func CreateBoard(c *gin.Context, firestoreClient *firestore.Client) {
+func CreateBoard(c *gin.Context, firestoreClient *firestore.Client, index search.Index) {
 	userId := c.MustGet("userId").(string)
 	var board dto.CreateBoardRequest
 	if err := c.ShouldBindJSON(&board); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
 		return
 	}
 
 	// ค้นหาผู้ใช้จากฐานข้อมูล
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	docSnap, err := services.GetUserDataByUserid(ctx, firestoreClient, userId)
 	if err != nil {
-		c.JSON(404, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 		return
 	}
 	// แปลงข้อมูลเป็น struct
 	var user model.User
 	if err := docSnap.DataTo(&user); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to parse user data"})
+		apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 		return
 	}
 
-	var grouptype string
-	switch board.Is_group {
-	case "0":
-		grouptype = "private"
-	case "1":
-		grouptype = "group"
-	default:
-		grouptype = "unknown" // กันกรณีค่าไม่ใช่ 0 หรือ 1
-	}
+	grouptype := board.Is_group.BoardType()
 
 	boardid := uuid.New().String()
 
@@ -62,12 +55,13 @@ func CreateBoard(c *gin.Context, firestoreClient *firestore.Client) {
 		BoardName: board.BoardName,
 		CreatedBy: user.UserID,
 		BoardType: grouptype,
+		Columns:   services.DefaultColumns(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	var deepLink string
-	if grouptype == "group" {
+	if grouptype == model.BoardGroup {
 		// สร้าง share token
 		expireAt := time.Now().Add(7 * 24 * time.Hour)
 		params := url.Values{}
@@ -84,9 +78,10 @@ func CreateBoard(c *gin.Context, firestoreClient *firestore.Client) {
 	// บันทึกข้อมูล Board ลง Firestore
 	_, err = firestoreClient.Collection("Boards").Doc(boardid).Set(ctx, newBoard)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create board"})
+		apperror.Abort(c, apperror.Internal(err, "failed to create board"))
 		return
 	}
+	services.IndexBoard(ctx, index, &newBoard)
 
 	// ส่งข้อมูลกลับไปยัง client
 	response := gin.H{
@@ -95,7 +90,7 @@ func CreateBoard(c *gin.Context, firestoreClient *firestore.Client) {
 	}
 
 	// เพิ่ม deepLink ใน response หากเป็น group
-	if grouptype == "group" {
+	if grouptype == model.BoardGroup {
 		response["deep_link"] = deepLink
 	}
 
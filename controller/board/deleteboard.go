@@ -0,0 +1,141 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"myapp/apperror"
+	"myapp/blobstore"
+	"myapp/middleware"
+	"myapp/search"
+	"myapp/services"
+	"net/http"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+func DeleteBoardController(router gin.IRouter, firestoreClient *firestore.Client, store blobstore.Store, index search.Index) {
+	router.DELETE("/boards/:boardid", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		DeleteBoard(c, firestoreClient, store, index)
+	})
+}
+
+// DeleteBoard ลบบอร์ดพร้อม task, reminder, subcollection ของ task, สมาชิก และไฟล์แนบทั้งหมด
+// เฉพาะเจ้าของบอร์ดเท่านั้น
+func DeleteBoard(c *gin.Context, firestoreClient *firestore.Client, store blobstore.Store, index search.Index) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+
+	ctx := c.Request.Context()
+	board, err := services.GetBoard(ctx, firestoreClient, boardId)
+	if err != nil {
+		if errors.Is(err, services.ErrBoardNotFound) {
+			apperror.Abort(c, apperror.New(apperror.CodeBoardNotFound))
+			return
+		}
+		apperror.Abort(c, apperror.Internal(err, "failed to get board"))
+		return
+	}
+	if board.CreatedBy != userId {
+		apperror.Abort(c, apperror.New(apperror.CodeBoardOwnerOnly))
+		return
+	}
+
+	refs, blobKeys, err := boardRelatedRefs(ctx, firestoreClient, boardId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to delete board"))
+		return
+	}
+
+	// บอร์ดอาจมีข้อมูลเกิน 500 รายการจึงใช้ BulkWriter แทน transaction
+	// ลบเอกสารลูกก่อนแล้วจึงลบตัวบอร์ด ถ้าล้มเหลวกลางทางสามารถเรียกซ้ำได้
+	writer := firestoreClient.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, ref := range refs {
+		job, err := writer.Delete(ref)
+		if err != nil {
+			writer.End()
+			apperror.Abort(c, apperror.Internal(err, "failed to delete board"))
+			return
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to delete board"))
+			return
+		}
+	}
+
+	if _, err := firestoreClient.Collection("Boards").Doc(boardId).Delete(ctx); err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to delete board"))
+		return
+	}
+	services.DeleteBlobs(ctx, store, blobKeys)
+
+	var taskIDs []string
+	for _, ref := range refs {
+		if ref.Parent.ID == "Tasks" {
+			taskIDs = append(taskIDs, ref.ID)
+		}
+	}
+	services.RemoveFromIndex(ctx, index, search.KindTask, taskIDs...)
+	services.RemoveFromIndex(ctx, index, search.KindBoard, boardId)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Board deleted successfully",
+		"boardID": boardId,
+	})
+}
+
+// boardRelatedRefs เอกสารทั้งหมดที่ต้องลบพร้อมบอร์ด และ key ของไฟล์แนบใน blobstore
+func boardRelatedRefs(ctx context.Context, firestoreClient *firestore.Client, boardID string) ([]*firestore.DocumentRef, []string, error) {
+	var refs []*firestore.DocumentRef
+	var blobKeys []string
+
+	members, err := firestoreClient.Collection("BoardUser").Where("boardid", "==", boardID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, doc := range members {
+		refs = append(refs, doc.Ref)
+	}
+
+	labels, err := services.LabelCollection(firestoreClient, boardID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, doc := range labels {
+		refs = append(refs, doc.Ref)
+	}
+
+	tasks, err := firestoreClient.Collection("Tasks").Where("boardid", "==", boardID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, task := range tasks {
+		notifications, err := firestoreClient.Collection("NotificationTasks").Where("taskid", "==", task.Ref.ID).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, doc := range notifications {
+			refs = append(refs, doc.Ref)
+		}
+
+		for _, collection := range services.TaskSubcollections(firestoreClient, task.Ref.ID) {
+			docs, err := collection.Documents(ctx).GetAll()
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, doc := range docs {
+				refs = append(refs, doc.Ref)
+				if key, ok := doc.Data()["storagekey"].(string); ok && key != "" {
+					blobKeys = append(blobKeys, key)
+				}
+			}
+		}
+		refs = append(refs, task.Ref)
+	}
+	return refs, blobKeys, nil
+}
@@ -0,0 +1,162 @@
+package board
+
+import (
+	"errors"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/search"
+	"myapp/services"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize จำกัดขนาดไฟล์ import ไว้ที่ 2 MB
+const maxImportFileSize = 2 << 20
+
+// multipartOverhead ขนาดของ boundary และ header ของ multipart ที่ยอมให้เกินขนาดไฟล์
+const multipartOverhead = 64 << 10
+
+func ImportTaskController(router gin.IRouter, firestoreClient *firestore.Client, index search.Index) {
+	router.POST("/boards/:boardid/import", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		ImportTasks(c, firestoreClient, index)
+	})
+}
+
+// ImportTasks นำเข้า task จากไฟล์ .ics หรือ .csv (form field "file")
+// ส่ง ?dryrun=true เพื่อตรวจสอบข้อมูลโดยไม่บันทึก
+func ImportTasks(c *gin.Context, firestoreClient *firestore.Client, index search.Index) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+	dryRun := c.Query("dryrun") == "true" || c.Query("dryrun") == "1"
+
+	ctx := c.Request.Context()
+	board, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId)
+	if err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	// จำกัดขนาด body ก่อน FormFile เพื่อไม่ให้ไฟล์ใหญ่ถูกอ่านลงดิสก์ทั้งไฟล์ก่อนตรวจขนาด
+	// เผื่อที่ไว้สำหรับ header ของ multipart
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+multipartOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apperror.Abort(c, apperror.New(apperror.CodeFileTooLarge).WithDetail("file must not exceed 2 MB"))
+			return
+		}
+		apperror.Abort(c, apperror.Invalid("file is required"))
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		apperror.Abort(c, apperror.New(apperror.CodeFileTooLarge).WithDetail("file must not exceed 2 MB"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest).WithDetail("failed to read file"))
+		return
+	}
+	defer file.Close()
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+
+	var rows []services.ImportRow
+	switch format {
+	case "ics", "ical":
+		rows, err = services.ParseICSImport(file, loc)
+	case "csv":
+		rows, err = services.ParseCSVImport(file, loc)
+	default:
+		apperror.Abort(c, apperror.New(apperror.CodeFileUnsupportedType).WithDetail("expected .ics or .csv"))
+		return
+	}
+	if err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeImportInvalid).WithDetail(err.Error()))
+		return
+	}
+	if len(rows) == 0 {
+		apperror.Abort(c, apperror.New(apperror.CodeImportInvalid).WithDetail("file does not contain any tasks"))
+		return
+	}
+
+	response := dto.ImportResponse{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]dto.ImportRowResult, 0, len(rows)),
+	}
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			response.Invalid++
+		}
+		response.Rows = append(response.Rows, dto.ImportRowResult{
+			Line:     row.Line,
+			TaskName: row.Task.TaskName,
+			DueDate:  services.FormatTaskTime(row.Task.DueDate, row.Task.AllDay, loc),
+			Valid:    len(row.Errors) == 0,
+			Errors:   row.Errors,
+		})
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// ถ้ามีแถวที่ไม่ถูกต้องจะไม่บันทึกเลยสักรายการ
+	if response.Invalid > 0 {
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	now := time.Now()
+	items := make([]services.NewTask, 0, len(rows))
+	for _, row := range rows {
+		task := row.Task
+		task.BoardID = boardId
+		task.CreatedBy = userId
+		// สถานะในไฟล์เป็นค่า "0"/"1"/"2" ต้องแปลงเป็นคอลัมน์ของบอร์ด
+		column := services.ColumnForLegacyStatus(board, task.Status)
+		task.Status = column.ColumnID
+		task.CompletedAt = services.CompletionTime(false, column.Done, nil, now)
+
+		item := services.NewTask{Task: task}
+		if row.Reminder != nil {
+			item.Reminders = append(item.Reminders, *row.Reminder)
+		}
+		items = append(items, item)
+	}
+
+	// บันทึกทั้งหมดใน transaction เดียว
+	if err := services.CreateTasks(ctx, firestoreClient, items); err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to import tasks"))
+		return
+	}
+	tasks := make([]model.Tasks, 0, len(items))
+	for _, item := range items {
+		response.TaskIDs = append(response.TaskIDs, item.Task.TaskID)
+		tasks = append(tasks, item.Task)
+	}
+	services.IndexTasks(ctx, index, tasks...)
+
+	response.Imported = len(response.TaskIDs)
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,264 @@
+package board
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errTooManyLabels  = apperror.New(apperror.CodeLabelLimit)
+	errDuplicateLabel = apperror.New(apperror.CodeLabelDuplicate)
+)
+
+func LabelController(router gin.IRouter, firestoreClient *firestore.Client) {
+	routes := router.Group("/boards/:boardid/labels", middleware.AccessTokenMiddleware())
+	{
+		routes.GET("", func(c *gin.Context) {
+			ListLabels(c, firestoreClient)
+		})
+		routes.POST("", func(c *gin.Context) {
+			CreateLabel(c, firestoreClient)
+		})
+		routes.PUT("/:labelid", func(c *gin.Context) {
+			UpdateLabel(c, firestoreClient)
+		})
+		routes.DELETE("/:labelid", func(c *gin.Context) {
+			DeleteLabel(c, firestoreClient)
+		})
+	}
+}
+
+func ListLabels(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+
+	ctx := c.Request.Context()
+	if _, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId); err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	labels, err := services.GetLabels(ctx, firestoreClient, boardId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get labels"))
+		return
+	}
+
+	response := make([]dto.LabelResponse, 0, len(labels))
+	for _, label := range labels {
+		response = append(response, toLabelResponse(label))
+	}
+	c.JSON(http.StatusOK, gin.H{"labels": response})
+}
+
+func CreateLabel(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+
+	var req dto.LabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+	name, color, err := services.NormalizeLabel(req.Name, req.Color)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId); err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	now := time.Now()
+	label := model.Label{
+		LabelID:   uuid.New().String(),
+		BoardID:   boardId,
+		Name:      name,
+		Color:     color,
+		CreatedBy: userId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	collection := services.LabelCollection(firestoreClient, boardId)
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(docs) >= services.MaxLabelsPerBoard {
+			return errTooManyLabels
+		}
+		if err := checkDuplicateLabel(docs, name, ""); err != nil {
+			return err
+		}
+		return tx.Create(collection.Doc(label.LabelID), label)
+	})
+	if err != nil {
+		respondLabelError(c, err, "failed to create label")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Label created successfully",
+		"label":   toLabelResponse(label),
+	})
+}
+
+func UpdateLabel(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+	labelId := c.Param("labelid")
+
+	var req dto.LabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+	name, color, err := services.NormalizeLabel(req.Name, req.Color)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId); err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	collection := services.LabelCollection(firestoreClient, boardId)
+	var updated model.Label
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return err
+		}
+		if err := checkDuplicateLabel(docs, name, labelId); err != nil {
+			return err
+		}
+
+		for _, doc := range docs {
+			if doc.Ref.ID != labelId {
+				continue
+			}
+			if err := doc.DataTo(&updated); err != nil {
+				return err
+			}
+			updated.Name, updated.Color, updated.UpdatedAt = name, color, time.Now()
+			return tx.Update(doc.Ref, []firestore.Update{
+				{Path: "name", Value: name},
+				{Path: "color", Value: color},
+				{Path: "updatedat", Value: updated.UpdatedAt},
+			})
+		}
+		return services.ErrLabelNotFound
+	})
+	if err != nil {
+		respondLabelError(c, err, "failed to update label")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Label updated successfully",
+		"label":   toLabelResponse(updated),
+	})
+}
+
+// DeleteLabel ลบ label และนำออกจากทุก task ในบอร์ด
+func DeleteLabel(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	boardId := c.Param("boardid")
+	labelId := c.Param("labelid")
+
+	ctx := c.Request.Context()
+	if _, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId); err != nil {
+		respondBoardError(c, err)
+		return
+	}
+
+	labelRef := services.LabelCollection(firestoreClient, boardId).Doc(labelId)
+	taskQuery := firestoreClient.Collection("Tasks").
+		Where("boardid", "==", boardId).
+		Where("labels", "array-contains", labelId)
+
+	err := firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := tx.Get(labelRef); err != nil {
+			if status.Code(err) == codes.NotFound {
+				return services.ErrLabelNotFound
+			}
+			return err
+		}
+		tasks, err := tx.Documents(taskQuery).GetAll()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, doc := range tasks {
+			if err := tx.Update(doc.Ref, []firestore.Update{
+				{Path: "labels", Value: firestore.ArrayRemove(labelId)},
+				{Path: "updatedat", Value: now},
+			}); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(labelRef)
+	})
+	if err != nil {
+		respondLabelError(c, err, "failed to delete label")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Label deleted successfully",
+		"labelid": labelId,
+	})
+}
+
+// checkDuplicateLabel ชื่อ label ในบอร์ดเดียวกันต้องไม่ซ้ำกัน (ไม่สนตัวพิมพ์เล็กใหญ่)
+func checkDuplicateLabel(docs []*firestore.DocumentSnapshot, name, exceptID string) error {
+	for _, doc := range docs {
+		if doc.Ref.ID == exceptID {
+			continue
+		}
+		if existing, ok := doc.Data()["name"].(string); ok && strings.EqualFold(existing, name) {
+			return errDuplicateLabel
+		}
+	}
+	return nil
+}
+
+func respondLabelError(c *gin.Context, err error, fallback string) {
+	apperror.Abort(c, apperror.OrInternal(err, fallback))
+}
+
+// respondBoardError ตอบ error จากการตรวจสิทธิ์บอร์ด (ไม่พบบอร์ดหรือไม่มีสิทธิ์)
+func respondBoardError(c *gin.Context, err error) {
+	apperror.Abort(c, apperror.OrInternal(err, "failed to get board"))
+}
+
+func toLabelResponse(label model.Label) dto.LabelResponse {
+	return dto.LabelResponse{
+		LabelID: label.LabelID,
+		BoardID: label.BoardID,
+		Name:    label.Name,
+		Color:   label.Color,
+	}
+}
@@ -0,0 +1,119 @@
+package board
+
+import (
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/pagination"
+	"net/http"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+func ListBoardController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.GET("/boards", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		ListBoards(c, firestoreClient)
+	})
+}
+
+// ListBoards บอร์ดที่ผู้ใช้สร้างและบอร์ดที่เป็นสมาชิก เรียงตาม boardid เพื่อให้รวมสองแหล่งแล้วแบ่งหน้าได้
+func ListBoards(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+
+	page, err := pagination.FromQuery(c, "boards:"+userId, pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	owned, err := page.Query(firestoreClient.Collection("Boards").
+		Where("createdby", "==", userId).
+		OrderBy(firestore.DocumentID, firestore.Asc)).Documents(ctx).GetAll()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+	// ต้องมี composite index: userid + boardid
+	memberships, err := page.Query(firestoreClient.Collection("BoardUser").
+		Where("userid", "==", userId).
+		OrderBy("boardid", firestore.Asc)).Documents(ctx).GetAll()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+
+	// แต่ละแหล่งดึงมาไม่เกิน limit+1 รายการหลัง cursor เดียวกัน
+	// limit+1 รายการแรกของผลรวมที่เรียงแล้วจึงถูกต้องเสมอ
+	snapshots := make(map[string]*firestore.DocumentSnapshot, len(owned))
+	ids := make([]string, 0, len(owned)+len(memberships))
+	for _, doc := range owned {
+		snapshots[doc.Ref.ID] = doc
+		ids = append(ids, doc.Ref.ID)
+	}
+	for _, doc := range memberships {
+		var boardUser model.BoardUser
+		if err := doc.DataTo(&boardUser); err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+			return
+		}
+		if _, ok := snapshots[boardUser.BoardID]; ok || boardUser.BoardID == "" {
+			continue
+		}
+		snapshots[boardUser.BoardID] = nil
+		ids = append(ids, boardUser.BoardID)
+	}
+	sort.Strings(ids)
+	ids, next, err := pagination.Next(page, ids, func(id string) []interface{} {
+		return []interface{}{id}
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+
+	var refs []*firestore.DocumentRef
+	for _, id := range ids {
+		if snapshots[id] == nil {
+			refs = append(refs, firestoreClient.Collection("Boards").Doc(id))
+		}
+	}
+	if len(refs) > 0 {
+		docs, err := firestoreClient.GetAll(ctx, refs)
+		if err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+			return
+		}
+		for _, doc := range docs {
+			snapshots[doc.Ref.ID] = doc
+		}
+	}
+
+	items := make([]dto.BoardResponse, 0, len(ids))
+	for _, id := range ids {
+		doc := snapshots[id]
+		// สมาชิกของบอร์ดที่ถูกลบไปแล้ว
+		if doc == nil || !doc.Exists() {
+			continue
+		}
+		var board model.Board
+		if err := doc.DataTo(&board); err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+			return
+		}
+		items = append(items, dto.BoardResponse{
+			BoardID:   doc.Ref.ID,
+			BoardName: board.BoardName,
+			Type:      board.BoardType,
+			DeepLink:  board.DeepLink,
+			CreatedBy: board.CreatedBy,
+			CreatedAt: board.CreatedAt.Format(time.RFC3339),
+			Owner:     board.CreatedBy == userId,
+		})
+	}
+	c.JSON(http.StatusOK, dto.NewPage(items, next))
+}
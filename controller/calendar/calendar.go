@@ -0,0 +1,171 @@
+package calendar
+
+import (
+	"errors"
+	"myapp/apperror"
+	"myapp/config"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+func CalendarController(router gin.IRouter, firestoreClient *firestore.Client, cfg *config.Config) {
+	routes := router.Group("/calendar")
+	{
+		routes.GET("", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+			GetCalendarFeed(c, firestoreClient, cfg)
+		})
+		routes.POST("/rotate", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+			RotateCalendarFeed(c, firestoreClient, cfg)
+		})
+
+		// feed ใช้ secret ใน URL แทน access token เพราะ calendar app ส่ง header ไม่ได้
+		routes.GET("/feeds/:secret", func(c *gin.Context) {
+			UserFeed(c, firestoreClient)
+		})
+		routes.GET("/feeds/:secret/boards/:boardid", func(c *gin.Context) {
+			BoardFeed(c, firestoreClient)
+		})
+	}
+}
+
+func GetCalendarFeed(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
+	userId := c.MustGet("userId").(string)
+
+	ctx := c.Request.Context()
+	feed, err := services.GetOrCreateFeed(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get calendar feed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, feedResponse(c, feed, cfg.Server.PublicURL))
+}
+
+func RotateCalendarFeed(c *gin.Context, firestoreClient *firestore.Client, cfg *config.Config) {
+	userId := c.MustGet("userId").(string)
+
+	ctx := c.Request.Context()
+	feed, err := services.RotateFeed(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to rotate calendar feed"))
+		return
+	}
+
+	response := feedResponse(c, feed, cfg.Server.PublicURL)
+	response["message"] = "Calendar feed rotated successfully"
+	c.JSON(http.StatusOK, response)
+}
+
+// feedResponse สร้าง URL จาก publicURL ที่ตั้งค่าไว้ ไม่ใช้ header ของคำขอซึ่ง client ปลอมได้
+// ถ้าไม่ได้ตั้งค่า (นอก production) จะใช้ host ที่ server ได้รับโดยตรง
+func feedResponse(c *gin.Context, feed *model.CalendarFeed, publicURL string) gin.H {
+	if publicURL == "" {
+		scheme := "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+		publicURL = scheme + "://" + c.Request.Host
+	}
+	base := strings.TrimSuffix(publicURL, "/") + "/v1/calendar/feeds/" + feed.Secret
+
+	return gin.H{
+		"url":          base + ".ics",
+		"boardUrl":     base + "/boards/{boardid}.ics",
+		"updatedAt":    feed.UpdatedAt,
+		"instructions": "Subscribe to this URL in Google Calendar or Apple Calendar. Rotate it if it is leaked.",
+	}
+}
+
+func UserFeed(c *gin.Context, firestoreClient *firestore.Client) {
+	ctx := c.Request.Context()
+	userId, ok := resolveFeedUser(c, firestoreClient)
+	if !ok {
+		return
+	}
+
+	boardIDs, err := services.GetAccessibleBoardIDs(ctx, firestoreClient, userId)
+	if err != nil {
+		respondFeedError(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+
+	renderFeed(c, firestoreClient, userId, "My Day Planner", boardIDs)
+}
+
+func BoardFeed(c *gin.Context, firestoreClient *firestore.Client) {
+	ctx := c.Request.Context()
+	userId, ok := resolveFeedUser(c, firestoreClient)
+	if !ok {
+		return
+	}
+
+	boardId := strings.TrimSuffix(c.Param("boardid"), ".ics")
+	board, err := services.GetBoardForUser(ctx, firestoreClient, boardId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrBoardNotFound) || errors.Is(err, services.ErrAccessDenied) {
+			respondFeedError(c, services.ErrBoardNotFound)
+			return
+		}
+		respondFeedError(c, apperror.Internal(err, "failed to get board"))
+		return
+	}
+
+	renderFeed(c, firestoreClient, userId, board.BoardName, []string{board.BoardID})
+}
+
+// resolveFeedUser หา userid จาก secret ใน URL (ตัด .ics ออกก่อน)
+func resolveFeedUser(c *gin.Context, firestoreClient *firestore.Client) (string, bool) {
+	secret := strings.TrimSuffix(c.Param("secret"), ".ics")
+	userId, err := services.GetFeedUserID(c.Request.Context(), firestoreClient, secret)
+	if err != nil {
+		if errors.Is(err, services.ErrFeedNotFound) {
+			respondFeedError(c, services.ErrFeedNotFound)
+			return "", false
+		}
+		respondFeedError(c, apperror.Internal(err, "failed to get calendar feed"))
+		return "", false
+	}
+	return userId, true
+}
+
+// respondFeedError ตอบ error ของ feed เป็นข้อความธรรมดาเพราะ calendar app ไม่อ่าน JSON
+// แต่ยังส่ง error ให้ middleware.ErrorHandler บันทึก log เหมือน endpoint อื่น
+func respondFeedError(c *gin.Context, err *apperror.Error) {
+	_ = c.Error(err)
+	c.String(apperror.Status(err.Code), apperror.Message(err.Code, apperror.LangEnglish))
+}
+
+func renderFeed(c *gin.Context, firestoreClient *firestore.Client, userId, name string, boardIDs []string) {
+	ctx := c.Request.Context()
+
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		respondFeedError(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	tasks, err := services.GetTasksByBoardIDs(ctx, firestoreClient, boardIDs)
+	if err != nil {
+		respondFeedError(c, apperror.Internal(err, "failed to get tasks"))
+		return
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	notifications, err := services.GetNotificationsByTaskIDs(ctx, firestoreClient, taskIDs)
+	if err != nil {
+		respondFeedError(c, apperror.Internal(err, "failed to get reminders"))
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(services.RenderICS(name, tasks, notifications, loc)))
+}
@@ -0,0 +1,30 @@
+package health
+
+import (
+	"myapp/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func HealthController(router *gin.Engine, checker *health.Checker) {
+	// liveness ตอบทันทีโดยไม่แตะส่วนที่พึ่งพา เพื่อไม่ให้ถูก restart เพราะ Firestore ช้าชั่วคราว
+	router.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+	})
+	router.GET("/readyz", func(c *gin.Context) {
+		Readiness(c, checker)
+	})
+}
+
+// Readiness ตอบ 503 เมื่อส่วนที่จำเป็นใช้งานไม่ได้ ส่วนที่ไม่จำเป็นจะทำให้สถานะเป็น degraded แต่ยังตอบ 200
+func Readiness(c *gin.Context, checker *health.Checker) {
+	report := checker.Run(c.Request.Context())
+
+	code := http.StatusOK
+	if report.Status == health.StatusDown {
+		code = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(code, report)
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"myapp/apperror"
+	"myapp/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsController เปิด /metrics ให้ Prometheus ถ้ากำหนด token ไว้ ต้องส่งมาเป็น Bearer token
+func MetricsController(router *gin.Engine, token string) {
+	handler := gin.WrapH(metrics.Handler())
+	router.GET("/metrics", func(c *gin.Context) {
+		if token != "" {
+			expected := []byte("Bearer " + token)
+			if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+				apperror.Abort(c, apperror.New(apperror.CodeUnauthorized))
+				return
+			}
+		}
+		handler(c)
+	})
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/pagination"
+	"myapp/services"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+func NotificationController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.GET("/notifications", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		ListNotifications(c, firestoreClient)
+	})
+}
+
+// ListNotifications การแจ้งเตือนของผู้ใช้ล่าสุดก่อน ส่ง ?unread=true เพื่อดูเฉพาะที่ยังไม่ได้อ่าน
+func ListNotifications(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	unreadOnly := c.Query("unread") == "true" || c.Query("unread") == "1"
+
+	scope := "notifications:" + userId
+	if unreadOnly {
+		scope += ":unread"
+	}
+	page, err := pagination.FromQuery(c, scope, pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	// ต้องมี composite index: userid + createdat desc และ userid + read + createdat desc
+	query := services.UserNotificationCollection(firestoreClient).Where("userid", "==", userId)
+	if unreadOnly {
+		query = query.Where("read", "==", "0")
+	}
+	query = query.OrderBy("createdat", firestore.Desc).OrderBy(firestore.DocumentID, firestore.Desc)
+
+	ctx := c.Request.Context()
+	docs, err := page.Query(query).Documents(ctx).GetAll()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get notifications"))
+		return
+	}
+
+	notifications := make([]model.UserNotification, 0, len(docs))
+	for _, doc := range docs {
+		var notification model.UserNotification
+		if err := doc.DataTo(&notification); err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get notifications"))
+			return
+		}
+		notifications = append(notifications, notification)
+	}
+	notifications, next, err := pagination.Next(page, notifications, func(notification model.UserNotification) []interface{} {
+		return []interface{}{notification.CreatedAt, notification.NotificationID}
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get notifications"))
+		return
+	}
+
+	items := make([]dto.UserNotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		items = append(items, dto.UserNotificationResponse{
+			NotificationID: notification.NotificationID,
+			Type:           notification.Type,
+			ActorID:        notification.ActorID,
+			BoardID:        notification.BoardID,
+			TaskID:         notification.TaskID,
+			CommentID:      notification.CommentID,
+			Message:        notification.Message,
+			Read:           notification.Read,
+			CreatedAt:      notification.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	c.JSON(http.StatusOK, dto.NewPage(items, next))
+}
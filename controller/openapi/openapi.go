@@ -0,0 +1,22 @@
+package openapi
+
+import (
+	"myapp/apperror"
+	"myapp/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIController เปิดเอกสาร OpenAPI ที่ /openapi.json ไม่ต้องยืนยันตัวตนและไม่ผูกกับเวอร์ชันของ API
+func OpenAPIController(router *gin.Engine) {
+	router.GET("/openapi.json", func(c *gin.Context) {
+		spec, err := openapi.JSON()
+		if err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to build openapi document"))
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=300")
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+}
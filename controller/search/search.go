@@ -0,0 +1,121 @@
+package search
+
+import (
+	"errors"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/pagination"
+	fulltext "myapp/search"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxQueryLength     = 100
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	snippetLength      = 160
+)
+
+func SearchController(router gin.IRouter, firestoreClient *firestore.Client, index fulltext.Index) {
+	router.GET("/search", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		Search(c, firestoreClient, index)
+	})
+}
+
+// Search ค้นหา task และบอร์ดที่ผู้ใช้เข้าถึงได้จากชื่อและคำอธิบาย
+// ?q=<คำค้น>&type=task|board&limit=&pageToken= ผลลัพธ์เรียงตามความเกี่ยวข้อง
+// token เก็บตำแหน่งในผลที่จัดอันดับแล้ว จึงผูกกับผู้ใช้ คำค้น และประเภท
+func Search(c *gin.Context, firestoreClient *firestore.Client, index fulltext.Index) {
+	userId := c.MustGet("userId").(string)
+
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" || utf8.RuneCountInString(text) > maxQueryLength {
+		apperror.Abort(c, apperror.Invalid("q must be between 1 and 100 characters"))
+		return
+	}
+
+	var kinds []fulltext.Kind
+	switch kind := fulltext.Kind(c.Query("type")); kind {
+	case "":
+	case fulltext.KindTask, fulltext.KindBoard:
+		kinds = append(kinds, kind)
+	default:
+		apperror.Abort(c, apperror.Invalid("type must be task or board"))
+		return
+	}
+
+	page, err := pagination.FromQuery(c, "search:"+userId+":"+c.Query("type")+":"+text, defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+	offset := 0
+	if after := page.After(); after != nil {
+		n, ok := after[0].(int64)
+		if len(after) != 1 || !ok || n < 0 {
+			apperror.Abort(c, pagination.ErrInvalidToken)
+			return
+		}
+		offset = int(n)
+	}
+
+	ctx := c.Request.Context()
+	boardIDs, err := services.GetAccessibleBoardIDs(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+
+	result, err := index.Search(ctx, fulltext.Query{
+		Text:     text,
+		BoardIDs: boardIDs,
+		Kinds:    kinds,
+		Limit:    page.Limit + 1,
+		Offset:   offset,
+	})
+	if err != nil {
+		if errors.Is(err, fulltext.ErrEmptyQuery) {
+			apperror.Abort(c, apperror.New(apperror.CodeSearchEmptyQuery))
+			return
+		}
+		apperror.Abort(c, apperror.Internal(err, "failed to search"))
+		return
+	}
+
+	items := make([]dto.SearchItem, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		items = append(items, dto.SearchItem{
+			Type:    string(hit.Kind),
+			ID:      hit.ID,
+			BoardID: hit.BoardID,
+			Title:   hit.Title,
+			Snippet: snippet(hit.Body),
+			Score:   hit.Score,
+		})
+	}
+	// Next เรียก cursor เฉพาะเมื่อหน้าเต็ม หน้าถัดไปจึงเริ่มที่ offset + Limit เสมอ
+	items, next, err := pagination.Next(page, items, func(dto.SearchItem) []interface{} {
+		return []interface{}{offset + page.Limit}
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to search"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPage(items, next))
+}
+
+func snippet(body string) string {
+	body = strings.Join(strings.Fields(body), " ")
+	if utf8.RuneCountInString(body) <= snippetLength {
+		return body
+	}
+	return string([]rune(body)[:snippetLength]) + "…"
+}
@@ -0,0 +1,263 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/pagination"
+	"myapp/services"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxAssignees จำนวนผู้รับผิดชอบสูงสุดต่อ task
+const maxAssignees = 20
+
+func TaskAssigneeController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.PUT("/tasks/:taskid/assignees", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		SetAssignees(c, firestoreClient)
+	})
+	router.GET("/tasks/assigned", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		GetAssignedTasks(c, firestoreClient)
+	})
+}
+
+// SetAssignees แทนที่ผู้รับผิดชอบทั้งหมดของ task (ส่ง [] เพื่อล้าง)
+// ผู้ที่ถูกเพิ่มหรือถูกนำออกจะได้รับการแจ้งเตือน ยกเว้นผู้ที่ทำรายการเอง
+func SetAssignees(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	var req dto.SetAssigneesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+	assignees := services.ParseIDList(req.UserIDs)
+	if len(assignees) > maxAssignees {
+		apperror.Abort(c, apperror.Invalid("A task can have at most 20 assignees"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, _, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	docRef := firestoreClient.Collection("Tasks").Doc(taskId)
+	err := firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return services.ErrTaskNotFound
+			}
+			return err
+		}
+		var current model.Tasks
+		if err := docSnap.DataTo(&current); err != nil {
+			return err
+		}
+
+		// อ่านสมาชิกใน transaction เพื่อไม่ให้มอบหมายผู้ที่เพิ่งถูกนำออกจากบอร์ด
+		memberIDs, err := services.GetBoardMemberIDsInTx(tx, firestoreClient, current.BoardID)
+		if err != nil {
+			return err
+		}
+		members := make(map[string]bool, len(memberIDs))
+		for _, id := range memberIDs {
+			members[id] = true
+		}
+		for _, id := range assignees {
+			if !members[id] {
+				return apperror.Invalid("assignee must be a member of the board").WithField("userid", id)
+			}
+		}
+
+		added, removed := diffIDs(current.Assignees, assignees)
+
+		var value interface{} = assignees
+		if len(assignees) == 0 {
+			value = firestore.Delete
+		}
+		if err := tx.Update(docRef, []firestore.Update{
+			{Path: "assignees", Value: value},
+			{Path: "updatedat", Value: time.Now()},
+		}); err != nil {
+			return err
+		}
+
+		if err := createAssignmentNotifications(firestoreClient, tx, &current, userId, added, services.NotificationAssigned,
+			fmt.Sprintf("You were assigned to %q", current.TaskName)); err != nil {
+			return err
+		}
+		return createAssignmentNotifications(firestoreClient, tx, &current, userId, removed, services.NotificationUnassigned,
+			fmt.Sprintf("You were unassigned from %q", current.TaskName))
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to update assignees"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Task assignees updated successfully",
+		"taskID":    taskId,
+		"assignees": dto.EmptyIfNil(assignees),
+	})
+}
+
+// maxAssignedScans จำนวนรอบสูงสุดที่อ่านต่อเมื่อ task ถูกกรองออกจนหน้าไม่เต็ม
+const maxAssignedScans = 5
+
+// GetAssignedTasks task ที่ผู้ใช้เป็นผู้รับผิดชอบจากทุกบอร์ดที่ยังเข้าถึงได้ เรียงจาก task ที่สร้างล่าสุด
+// ไม่รวม task ที่ทำเสร็จแล้ว เว้นแต่ส่ง ?includecompleted=true
+// ตัวกรองรวมกับ array-contains ใน query เดียวไม่ได้ จึงอ่านต่อหลายรอบจนหน้าเต็ม
+// ถ้าอ่านครบ maxAssignedScans รอบแล้วยังไม่เต็ม จะคืนเท่าที่ได้พร้อม token ต่อจากรายการสุดท้ายที่อ่าน
+func GetAssignedTasks(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	includeCompleted := c.Query("includecompleted") == "true" || c.Query("includecompleted") == "1"
+	labelIDs := services.ParseIDList(c.QueryArray("label"))
+
+	page, err := pagination.FromQuery(c, "assigned:"+userId, pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	boardIDs, err := services.GetAccessibleBoardIDs(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get boards"))
+		return
+	}
+	accessible := make(map[string]bool, len(boardIDs))
+	for _, id := range boardIDs {
+		accessible[id] = true
+	}
+
+	// ต้องมี composite index: assignees (array-contains) + createdat desc
+	query := firestoreClient.Collection("Tasks").
+		Where("assignees", "array-contains", userId).
+		OrderBy("createdat", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc)
+	cursor := func(task model.Tasks) []interface{} {
+		return []interface{}{task.CreatedAt, task.TaskID}
+	}
+
+	var visible []model.Tasks
+	var next string
+	scan := page
+	for round := 1; ; round++ {
+		docs, err := scan.Query(query).Documents(ctx).GetAll()
+		if err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+			return
+		}
+
+		var last model.Tasks
+		tasks := make([]model.Tasks, 0, len(docs))
+		for _, doc := range docs {
+			var task model.Tasks
+			if err := doc.DataTo(&task); err != nil {
+				apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+				return
+			}
+			last = task
+			// ผู้ใช้ที่ออกจากบอร์ดไปแล้วจะไม่เห็น task ของบอร์ดนั้น
+			if accessible[task.BoardID] && (includeCompleted || !services.IsTaskCompleted(&task)) {
+				tasks = append(tasks, task)
+			}
+		}
+		visible = append(visible, services.FilterTasksByLabels(tasks, labelIDs)...)
+
+		if len(docs) <= page.Limit || len(visible) > page.Limit {
+			break
+		}
+		if round == maxAssignedScans {
+			if next, err = page.Token(cursor(last)); err != nil {
+				apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+				return
+			}
+			break
+		}
+		scan = page.StartAfter(cursor(last)...)
+	}
+	if next == "" {
+		visible, next, err = pagination.Next(page, visible, cursor)
+		if err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get tasks"))
+			return
+		}
+	}
+
+	items := make([]dto.TaskSummary, 0, len(visible))
+	for _, task := range visible {
+		items = append(items, toTaskSummary(task, loc))
+	}
+	c.JSON(http.StatusOK, dto.NewPage(items, next))
+}
+
+func createAssignmentNotifications(firestoreClient *firestore.Client, tx *firestore.Transaction, task *model.Tasks, actorID string, userIDs []string, notificationType, message string) error {
+	for _, id := range userIDs {
+		if id == actorID {
+			continue
+		}
+		notification := services.NewUserNotification(id, notificationType, actorID, message)
+		notification.BoardID = task.BoardID
+		notification.TaskID = task.TaskID
+		if err := tx.Create(services.UserNotificationCollection(firestoreClient).Doc(notification.NotificationID), notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffIDs คืนค่า id ที่เพิ่มเข้ามาใหม่และ id ที่ถูกนำออก
+func diffIDs(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, id := range before {
+		inBefore[id] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, id := range after {
+		inAfter[id] = true
+		if !inBefore[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range before {
+		if !inAfter[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+func toTaskSummary(task model.Tasks, loc *time.Location) dto.TaskSummary {
+	return dto.TaskSummary{
+		TaskID:    task.TaskID,
+		BoardID:   task.BoardID,
+		TaskName:  task.TaskName,
+		Status:    task.Status,
+		Priority:  task.Priority,
+		DueDate:   services.FormatTaskTime(task.DueDate, task.AllDay, loc),
+		AllDay:    task.AllDay,
+		Position:  task.Position,
+		Labels:    dto.EmptyIfNil(task.Labels),
+		Assignees: dto.EmptyIfNil(task.Assignees),
+	}
+}
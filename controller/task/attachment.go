@@ -0,0 +1,283 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"myapp/apperror"
+	"myapp/blobstore"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// multipartOverhead ขนาดของ boundary และ header ของ multipart ที่ยอมให้เกินขนาดไฟล์
+const multipartOverhead = 64 << 10
+
+var (
+	errAttachmentNotFound  = apperror.New(apperror.CodeAttachmentNotFound)
+	errAttachmentForbidden = apperror.New(apperror.CodeAttachmentForbidden)
+	errTooManyAttachments  = apperror.New(apperror.CodeAttachmentLimit)
+)
+
+func AttachmentController(router gin.IRouter, firestoreClient *firestore.Client, store blobstore.Store) {
+	routes := router.Group("/tasks/:taskid/attachments", middleware.AccessTokenMiddleware())
+	{
+		routes.GET("", func(c *gin.Context) {
+			ListAttachments(c, firestoreClient)
+		})
+		routes.POST("", func(c *gin.Context) {
+			UploadAttachment(c, firestoreClient, store)
+		})
+		routes.GET("/:attachmentid", func(c *gin.Context) {
+			DownloadAttachment(c, firestoreClient, store)
+		})
+		routes.DELETE("/:attachmentid", func(c *gin.Context) {
+			DeleteAttachment(c, firestoreClient, store)
+		})
+	}
+}
+
+func ListAttachments(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	attachments, err := services.GetAttachments(ctx, firestoreClient, taskId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get attachments"))
+		return
+	}
+
+	response := make([]dto.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		response = append(response, toAttachmentResponse(attachment))
+	}
+	c.JSON(http.StatusOK, gin.H{"attachments": response})
+}
+
+// UploadAttachment อัปโหลดไฟล์ผ่าน form field "file"
+// เก็บไฟล์ลง blobstore ก่อน แล้วจึงบันทึกเอกสารใน Firestore ถ้าบันทึกไม่สำเร็จจะลบไฟล์ทิ้ง
+func UploadAttachment(c *gin.Context, firestoreClient *firestore.Client, store blobstore.Store) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	ctx := c.Request.Context()
+	task, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	// จำกัดขนาด body ก่อน FormFile เพื่อไม่ให้ไฟล์ใหญ่ถูกอ่านลงดิสก์ทั้งไฟล์ก่อนตรวจขนาด
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAttachmentSize+multipartOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apperror.Abort(c, apperror.New(apperror.CodeFileTooLarge).WithDetail("file must not exceed 10 MB"))
+			return
+		}
+		apperror.Abort(c, apperror.Invalid("file is required"))
+		return
+	}
+	if fileHeader.Size > services.MaxAttachmentSize {
+		apperror.Abort(c, apperror.New(apperror.CodeFileTooLarge).WithDetail("file must not exceed 10 MB"))
+		return
+	}
+	if fileHeader.Size == 0 {
+		apperror.Abort(c, apperror.Invalid("File is empty"))
+		return
+	}
+
+	fileName := strings.TrimSpace(filepath.Base(fileHeader.Filename))
+	if fileName == "" || fileName == "." || len(fileName) > 255 {
+		apperror.Abort(c, apperror.Invalid("Invalid file name"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest).WithDetail("failed to read file"))
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest).WithDetail("failed to read file"))
+		return
+	}
+	head = head[:n]
+
+	contentType, err := services.DetectAttachmentType(head, fileName)
+	if err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeFileUnsupportedType).WithDetail("allowed: images, PDF, text, CSV and Office documents"))
+		return
+	}
+
+	attachment := model.Attachment{
+		AttachmentID: uuid.New().String(),
+		TaskID:       taskId,
+		BoardID:      task.BoardID,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Size:         fileHeader.Size,
+		UploadedBy:   userId,
+		CreatedAt:    time.Now(),
+	}
+	attachment.StorageKey = services.AttachmentKey(taskId, attachment.AttachmentID)
+
+	content := io.MultiReader(bytes.NewReader(head), file)
+	if err := store.Put(ctx, attachment.StorageKey, content, contentType); err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to store file"))
+		return
+	}
+
+	collection := services.AttachmentCollection(firestoreClient, taskId)
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(docs) >= services.MaxAttachmentsPerTask {
+			return errTooManyAttachments
+		}
+		return tx.Create(collection.Doc(attachment.AttachmentID), attachment)
+	})
+	if err != nil {
+		services.DeleteBlobs(ctx, store, []string{attachment.StorageKey})
+		apperror.Abort(c, apperror.OrInternal(err, "failed to save attachment"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Attachment uploaded successfully",
+		"attachment": toAttachmentResponse(attachment),
+	})
+}
+
+func DownloadAttachment(c *gin.Context, firestoreClient *firestore.Client, store blobstore.Store) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	attachment, err := getAttachment(ctx, firestoreClient, taskId, c.Param("attachmentid"))
+	if err != nil {
+		respondAttachmentError(c, err, "failed to get attachment")
+		return
+	}
+
+	reader, err := store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			apperror.Abort(c, errAttachmentNotFound.WithDetail("file not found"))
+			return
+		}
+		apperror.Abort(c, apperror.Internal(err, "failed to read file"))
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, reader, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment ลบได้โดยผู้อัปโหลดหรือเจ้าของบอร์ด
+func DeleteAttachment(c *gin.Context, firestoreClient *firestore.Client, store blobstore.Store) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+	attachmentId := c.Param("attachmentid")
+
+	ctx := c.Request.Context()
+	_, board, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	attachment, err := getAttachment(ctx, firestoreClient, taskId, attachmentId)
+	if err != nil {
+		respondAttachmentError(c, err, "failed to get attachment")
+		return
+	}
+	if attachment.UploadedBy != userId && board.CreatedBy != userId {
+		apperror.Abort(c, errAttachmentForbidden)
+		return
+	}
+
+	// ลบเอกสารก่อน ถ้าลบไฟล์ไม่สำเร็จจะเหลือแค่ไฟล์ที่ไม่มีใครอ้างอิง
+	docRef := services.AttachmentCollection(firestoreClient, taskId).Doc(attachmentId)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			apperror.Abort(c, errAttachmentNotFound)
+			return
+		}
+		apperror.Abort(c, apperror.Internal(err, "failed to delete attachment"))
+		return
+	}
+	services.DeleteBlobs(ctx, store, []string{attachment.StorageKey})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Attachment deleted successfully",
+		"attachmentid": attachmentId,
+	})
+}
+
+func getAttachment(ctx context.Context, firestoreClient *firestore.Client, taskID, attachmentID string) (*model.Attachment, error) {
+	docSnap, err := services.AttachmentCollection(firestoreClient, taskID).Doc(attachmentID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errAttachmentNotFound
+		}
+		return nil, err
+	}
+
+	var attachment model.Attachment
+	if err := docSnap.DataTo(&attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func respondAttachmentError(c *gin.Context, err error, fallback string) {
+	apperror.Abort(c, apperror.OrInternal(err, fallback))
+}
+
+func toAttachmentResponse(attachment model.Attachment) dto.AttachmentResponse {
+	return dto.AttachmentResponse{
+		AttachmentID: attachment.AttachmentID,
+		TaskID:       attachment.TaskID,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		UploadedBy:   attachment.UploadedBy,
+		CreatedAt:    attachment.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,378 @@
+package task
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/blobstore"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/search"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxBatchOperations จำนวนรายการสูงสุดต่อหนึ่ง request
+	maxBatchOperations = 100
+	// maxBatchWrites Firestore รองรับการเขียนได้ไม่เกิน 500 รายการต่อ transaction
+	maxBatchWrites = 500
+)
+
+var (
+	errBatchTaskChanged = apperror.New(apperror.CodeTaskConflict).WithDetail("task was deleted while processing the batch")
+	errTooManyWrites    = apperror.New(apperror.CodeBatchTooLarge)
+)
+
+func BatchTaskController(router gin.IRouter, firestoreClient *firestore.Client, store blobstore.Store, index search.Index) {
+	router.POST("/tasks/batch", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		BatchTasks(c, firestoreClient, store, index)
+	})
+}
+
+// BatchTasks ทำหลายรายการในครั้งเดียวแบบ all-or-nothing
+// ตรวจสอบทุกรายการก่อน ถ้ามีรายการใดผิดจะไม่บันทึกเลย แล้วจึงเขียนทั้งหมดใน transaction เดียว
+func BatchTasks(c *gin.Context, firestoreClient *firestore.Client, store blobstore.Store, index search.Index) {
+	userId := c.MustGet("userId").(string)
+
+	var req dto.BatchTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
+		return
+	}
+	if len(req.Operations) == 0 || len(req.Operations) > maxBatchOperations {
+		apperror.Abort(c, apperror.Invalid("operations must contain between 1 and 100 items"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	results := make([]dto.BatchTaskResult, len(req.Operations))
+	tasks := make(map[string]*model.Tasks)
+	boards := make(map[string]*model.Board)
+	boardErrors := make(map[string]error)
+	failed := false
+
+	checkBoard := func(boardID string) (*model.Board, error) {
+		if err, ok := boardErrors[boardID]; ok {
+			return boards[boardID], err
+		}
+		board, err := services.GetBoardForUser(ctx, firestoreClient, boardID, userId)
+		boards[boardID], boardErrors[boardID] = board, err
+		return board, err
+	}
+
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		op.Op = strings.ToLower(strings.TrimSpace(op.Op))
+		results[i] = dto.BatchTaskResult{Index: i, Op: op.Op, TaskID: op.TaskID}
+
+		if err := validateBatchOperation(op, tasks, checkBoard, func(taskID string) (*model.Tasks, error) {
+			return services.GetTaskForUser(ctx, firestoreClient, taskID, userId)
+		}); err != nil {
+			appErr := apperror.OrInternal(err, "failed to validate operation")
+			results[i].Code = string(appErr.Code)
+			results[i].Error = batchErrorMessage(c, appErr)
+			failed = true
+			continue
+		}
+		if op.Op == "create" {
+			op.TaskID = uuid.New().String()
+			results[i].TaskID = op.TaskID
+		}
+		results[i].OK = true
+	}
+
+	if failed {
+		for i := range results {
+			if results[i].OK {
+				results[i].OK = false
+				results[i].Error = "not applied because another operation failed"
+			}
+		}
+		c.JSON(http.StatusBadRequest, dto.BatchTaskResponse{Success: false, Results: results})
+		return
+	}
+
+	// ผู้รับผิดชอบที่ไม่ใช่สมาชิกของบอร์ดปลายทางจะถูกนำออกเมื่อย้าย task
+	targetMembers := make(map[string]map[string]bool)
+	for _, op := range req.Operations {
+		if op.Op != "move" || targetMembers[op.TargetBoardID] != nil {
+			continue
+		}
+		memberIDs, err := services.GetBoardMemberIDs(ctx, firestoreClient, boards[op.TargetBoardID])
+		if err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get board members"))
+			return
+		}
+		members := make(map[string]bool, len(memberIDs))
+		for _, id := range memberIDs {
+			members[id] = true
+		}
+		targetMembers[op.TargetBoardID] = members
+	}
+
+	now := time.Now()
+	tasksRef := firestoreClient.Collection("Tasks")
+	var blobKeys []string
+	err := firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// อ่านข้อมูลทั้งหมดก่อนเขียน ตามข้อกำหนดของ transaction
+		current := make(map[string]model.Tasks)
+		related := make(map[string][]*firestore.DocumentRef)
+		blobKeys = nil // transaction อาจถูกเรียกซ้ำ
+		for _, op := range req.Operations {
+			if op.Op == "create" {
+				continue
+			}
+			docSnap, err := tx.Get(tasksRef.Doc(op.TaskID))
+			if err != nil {
+				if status.Code(err) == codes.NotFound {
+					return errBatchTaskChanged
+				}
+				return err
+			}
+			var task model.Tasks
+			if err := docSnap.DataTo(&task); err != nil {
+				return err
+			}
+			current[op.TaskID] = task
+
+			if op.Op == "delete" {
+				refs, keys, err := taskRelatedRefs(firestoreClient, tx, op.TaskID)
+				if err != nil {
+					return err
+				}
+				related[op.TaskID] = refs
+				blobKeys = append(blobKeys, keys...)
+			}
+		}
+
+		writes := 0
+		for _, refs := range related {
+			writes += len(refs)
+		}
+		if writes+len(req.Operations) > maxBatchWrites {
+			return errTooManyWrites
+		}
+
+		for i, op := range req.Operations {
+			switch op.Op {
+			case "create":
+				column, _ := services.ResolveStatus(boards[op.BoardID], valueOr(op.Status, ""))
+				newTask := model.Tasks{
+					TaskID:      op.TaskID,
+					BoardID:     op.BoardID,
+					TaskName:    op.TaskName,
+					Description: op.Description,
+					Status:      column.ColumnID,
+					Priority:    valueOr(op.Priority, ""),
+					CreatedBy:   userId,
+					Position:    services.InitialPosition(now) + float64(i)/1000,
+					CompletedAt: services.CompletionTime(false, column.Done, nil, now),
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}
+				if err := tx.Create(tasksRef.Doc(op.TaskID), newTask); err != nil {
+					return err
+				}
+
+			case "update":
+				task := current[op.TaskID]
+				updates := []firestore.Update{{Path: "updatedat", Value: now}}
+				if op.Priority != nil {
+					updates = append(updates, firestore.Update{Path: "priority", Value: *op.Priority})
+				}
+				if op.Status != nil && *op.Status != task.Status {
+					column, _, _ := services.FindColumn(services.BoardColumns(boards[task.BoardID]), *op.Status)
+					updates = append(updates,
+						firestore.Update{Path: "status", Value: column.ColumnID},
+						firestore.Update{Path: "position", Value: services.InitialPosition(now) + float64(i)/1000},
+						timeUpdate("completedat", services.CompletionTime(services.IsTaskCompleted(&task), column.Done, task.CompletedAt, now)),
+					)
+				}
+				if err := tx.Update(tasksRef.Doc(op.TaskID), updates); err != nil {
+					return err
+				}
+
+			case "move":
+				task := current[op.TaskID]
+				var assignees []string
+				for _, id := range task.Assignees {
+					if targetMembers[op.TargetBoardID][id] {
+						assignees = append(assignees, id)
+					}
+				}
+				var assigneesValue interface{} = assignees
+				if len(assignees) == 0 {
+					assigneesValue = firestore.Delete
+				}
+
+				// คงคอลัมน์เดิมไว้ถ้าบอร์ดปลายทางมีคอลัมน์นั้น ไม่เช่นนั้นไปคอลัมน์แรก
+				targetColumns := services.BoardColumns(boards[op.TargetBoardID])
+				column, _, ok := services.FindColumn(targetColumns, task.Status)
+				if !ok {
+					column = targetColumns[0]
+				}
+
+				// label เป็นของแต่ละบอร์ด จึงต้องล้างออกเมื่อย้ายบอร์ด
+				if err := tx.Update(tasksRef.Doc(op.TaskID), []firestore.Update{
+					{Path: "boardid", Value: op.TargetBoardID},
+					{Path: "status", Value: column.ColumnID},
+					{Path: "position", Value: services.InitialPosition(now) + float64(i)/1000},
+					timeUpdate("completedat", services.CompletionTime(services.IsTaskCompleted(&task), column.Done, task.CompletedAt, now)),
+					{Path: "labels", Value: firestore.Delete},
+					{Path: "assignees", Value: assigneesValue},
+					{Path: "updatedat", Value: now},
+				}); err != nil {
+					return err
+				}
+
+			case "delete":
+				for _, ref := range related[op.TaskID] {
+					if err := tx.Delete(ref); err != nil {
+						return err
+					}
+				}
+				if err := tx.Delete(tasksRef.Doc(op.TaskID)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to apply batch"))
+		return
+	}
+	services.DeleteBlobs(ctx, store, blobKeys)
+
+	// update แก้เฉพาะ status และ priority ซึ่งไม่อยู่ในดัชนีค้นหา
+	var changed, deleted []string
+	for _, op := range req.Operations {
+		switch op.Op {
+		case "create", "move":
+			changed = append(changed, op.TaskID)
+		case "delete":
+			deleted = append(deleted, op.TaskID)
+		}
+	}
+	services.ReindexTasks(ctx, firestoreClient, index, changed...)
+	services.RemoveFromIndex(ctx, index, search.KindTask, deleted...)
+
+	c.JSON(http.StatusOK, dto.BatchTaskResponse{Success: true, Results: results})
+}
+
+// validateBatchOperation ตรวจสอบรูปแบบและสิทธิ์ของแต่ละรายการโดยยังไม่เขียนข้อมูล
+func validateBatchOperation(op *dto.BatchTaskOperation, seen map[string]*model.Tasks, checkBoard func(string) (*model.Board, error), loadTask func(string) (*model.Tasks, error)) error {
+	switch op.Op {
+	case "create":
+		op.TaskName = strings.TrimSpace(op.TaskName)
+		if op.BoardID == "" || op.TaskName == "" {
+			return apperror.Invalid("boardid and taskname are required")
+		}
+		board, err := checkBoard(op.BoardID)
+		if err != nil {
+			return batchBoardError(err)
+		}
+		_, err = services.ResolveStatus(board, valueOr(op.Status, ""))
+		return err
+
+	case "update", "move", "delete":
+		if op.TaskID == "" {
+			return apperror.Invalid("taskid is required")
+		}
+		if _, dup := seen[op.TaskID]; dup {
+			return apperror.Invalid("taskid appears more than once in the batch")
+		}
+		if op.Op == "update" && op.Status == nil && op.Priority == nil {
+			return apperror.Invalid("status or priority is required")
+		}
+		if op.Op == "move" && op.TargetBoardID == "" {
+			return apperror.Invalid("targetboardid is required")
+		}
+
+		task, err := loadTask(op.TaskID)
+		if err != nil {
+			return batchBoardError(err)
+		}
+		seen[op.TaskID] = task
+
+		if op.Op == "move" {
+			if op.TargetBoardID == task.BoardID {
+				return apperror.Invalid("task is already on the target board")
+			}
+			_, err := checkBoard(op.TargetBoardID)
+			return batchBoardError(err)
+		}
+		if op.Op == "update" && op.Status != nil {
+			board, err := checkBoard(task.BoardID)
+			if err != nil {
+				return batchBoardError(err)
+			}
+			if _, _, ok := services.FindColumn(services.BoardColumns(board), *op.Status); !ok {
+				return services.ErrUnknownStatus
+			}
+		}
+		return nil
+	}
+	return apperror.Invalid("op must be one of create, update, move, delete")
+}
+
+// batchBoardError ส่งต่อ error ที่ client เข้าใจได้ (ไม่พบหรือไม่มีสิทธิ์) ส่วน error อื่นถือเป็น error ภายใน
+func batchBoardError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return apperror.OrInternal(err, "failed to validate operation")
+}
+
+// batchErrorMessage ข้อความของรายการที่ไม่ผ่านการตรวจสอบ ใช้ detail ถ้ามี ไม่เช่นนั้นใช้ข้อความตามภาษาผู้ใช้
+func batchErrorMessage(c *gin.Context, appErr *apperror.Error) string {
+	if appErr.Detail != "" {
+		return appErr.Detail
+	}
+	return apperror.Message(appErr.Code, apperror.Language(c.GetHeader("Accept-Language")))
+}
+
+// taskRelatedRefs เอกสารที่ต้องลบพร้อม task (reminder และ subcollection ทั้งหมด)
+// พร้อม key ของไฟล์แนบที่ต้องลบออกจาก blobstore หลัง commit
+func taskRelatedRefs(firestoreClient *firestore.Client, tx *firestore.Transaction, taskID string) ([]*firestore.DocumentRef, []string, error) {
+	var refs []*firestore.DocumentRef
+	var blobKeys []string
+
+	notifications, err := tx.Documents(firestoreClient.Collection("NotificationTasks").Where("taskid", "==", taskID)).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, doc := range notifications {
+		refs = append(refs, doc.Ref)
+	}
+
+	for _, collection := range services.TaskSubcollections(firestoreClient, taskID) {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, doc := range docs {
+			refs = append(refs, doc.Ref)
+			if key, ok := doc.Data()["storagekey"].(string); ok && key != "" {
+				blobKeys = append(blobKeys, key)
+			}
+		}
+	}
+	return refs, blobKeys, nil
+}
+
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
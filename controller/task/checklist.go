@@ -0,0 +1,249 @@
+package task
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errChecklistNotFound = apperror.New(apperror.CodeChecklistNotFound)
+	errInvalidOrder      = apperror.Invalid("checklistids must contain every checklist of the task exactly once")
+)
+
+func ChecklistController(router gin.IRouter, firestoreClient *firestore.Client) {
+	routes := router.Group("/tasks/:taskid/checklist", middleware.AccessTokenMiddleware())
+	{
+		routes.POST("", func(c *gin.Context) {
+			AddChecklist(c, firestoreClient)
+		})
+		routes.PUT("/reorder", func(c *gin.Context) {
+			ReorderChecklist(c, firestoreClient)
+		})
+		routes.PUT("/:checklistid/toggle", func(c *gin.Context) {
+			ToggleChecklist(c, firestoreClient)
+		})
+		routes.DELETE("/:checklistid", func(c *gin.Context) {
+			DeleteChecklist(c, firestoreClient)
+		})
+	}
+}
+
+func AddChecklist(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	var req dto.CreateChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" || len(req.Title) > 200 {
+		apperror.Abort(c, apperror.Invalid("Title must be between 1 and 200 characters"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	checklists, err := services.GetChecklists(ctx, firestoreClient, taskId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get checklists"))
+		return
+	}
+
+	// ต่อท้ายรายการเดิม
+	order := 0
+	if len(checklists) > 0 {
+		order = checklists[len(checklists)-1].Order + 1
+	}
+
+	checklistId := uuid.New().String()
+	newChecklist := model.Checklist{
+		ChecklistID: checklistId,
+		TaskID:      taskId,
+		Title:       req.Title,
+		Status:      "0",
+		Order:       order,
+		CreatedBy:   userId,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err = services.ChecklistCollection(firestoreClient, taskId).Doc(checklistId).Set(ctx, newChecklist)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to create checklist"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Checklist created successfully",
+		"checklist": toChecklistResponse(newChecklist),
+		"progress":  services.ChecklistProgress(append(checklists, newChecklist)),
+	})
+}
+
+func ToggleChecklist(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+	checklistId := c.Param("checklistid")
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	docRef := services.ChecklistCollection(firestoreClient, taskId).Doc(checklistId)
+
+	var newStatus string
+	err := firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return errChecklistNotFound
+			}
+			return err
+		}
+
+		var item model.Checklist
+		if err := docSnap.DataTo(&item); err != nil {
+			return err
+		}
+
+		newStatus = "1"
+		if item.Status == "1" {
+			newStatus = "0"
+		}
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "status", Value: newStatus},
+			{Path: "updatedat", Value: time.Now()},
+		})
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to update checklist"))
+		return
+	}
+
+	checklists, err := services.GetChecklists(ctx, firestoreClient, taskId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get checklists"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Checklist updated successfully",
+		"checklistid": checklistId,
+		"status":      newStatus,
+		"progress":    services.ChecklistProgress(checklists),
+	})
+}
+
+func ReorderChecklist(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	var req dto.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	collection := services.ChecklistCollection(firestoreClient, taskId)
+	err := firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return err
+		}
+
+		// รายการที่ส่งมาต้องครบทุกรายการและไม่ซ้ำกัน
+		if len(docs) != len(req.ChecklistIDs) {
+			return errInvalidOrder
+		}
+		existing := make(map[string]bool, len(docs))
+		for _, doc := range docs {
+			existing[doc.Ref.ID] = true
+		}
+		for _, id := range req.ChecklistIDs {
+			if !existing[id] {
+				return errInvalidOrder
+			}
+			delete(existing, id)
+		}
+
+		now := time.Now()
+		for i, id := range req.ChecklistIDs {
+			err := tx.Update(collection.Doc(id), []firestore.Update{
+				{Path: "order", Value: i},
+				{Path: "updatedat", Value: now},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to reorder checklists"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Checklists reordered successfully"})
+}
+
+func DeleteChecklist(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+	checklistId := c.Param("checklistid")
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	docRef := services.ChecklistCollection(firestoreClient, taskId).Doc(checklistId)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			apperror.Abort(c, apperror.New(apperror.CodeChecklistNotFound))
+			return
+		}
+		apperror.Abort(c, apperror.Internal(err, "failed to delete checklist"))
+		return
+	}
+
+	checklists, err := services.GetChecklists(ctx, firestoreClient, taskId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get checklists"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Checklist deleted successfully",
+		"progress": services.ChecklistProgress(checklists),
+	})
+}
@@ -0,0 +1,342 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/pagination"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxCommentLength    = 2000
+	defaultCommentLimit = 20
+	maxCommentLimit     = 50
+	// commentEditWindow แก้ไขความคิดเห็นได้ภายในเวลานี้หลังจากสร้าง
+	commentEditWindow = 15 * time.Minute
+)
+
+var (
+	errCommentNotFound  = apperror.New(apperror.CodeCommentNotFound)
+	errCommentForbidden = apperror.New(apperror.CodeCommentForbidden)
+	errEditWindowClosed = apperror.New(apperror.CodeCommentEditWindowClosed)
+)
+
+func CommentController(router gin.IRouter, firestoreClient *firestore.Client) {
+	routes := router.Group("/tasks/:taskid/comments", middleware.AccessTokenMiddleware())
+	{
+		routes.GET("", func(c *gin.Context) {
+			ListComments(c, firestoreClient)
+		})
+		routes.POST("", func(c *gin.Context) {
+			CreateComment(c, firestoreClient)
+		})
+		routes.PUT("/:commentid", func(c *gin.Context) {
+			EditComment(c, firestoreClient)
+		})
+		routes.DELETE("/:commentid", func(c *gin.Context) {
+			DeleteComment(c, firestoreClient)
+		})
+	}
+}
+
+// ListComments ดึงความคิดเห็นล่าสุดก่อน ส่ง ?pageToken=<nextPageToken> เพื่อดึงหน้าถัดไป
+func ListComments(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	page, err := pagination.FromQuery(c, "comments:"+taskId, defaultCommentLimit, maxCommentLimit)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId); err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	query := services.CommentCollection(firestoreClient, taskId).
+		OrderBy("createdat", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc)
+	docs, err := page.Query(query).Documents(ctx).GetAll()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get comments"))
+		return
+	}
+
+	comments := make([]model.Comment, 0, len(docs))
+	for _, doc := range docs {
+		var comment model.Comment
+		if err := doc.DataTo(&comment); err != nil {
+			apperror.Abort(c, apperror.Internal(err, "failed to get comments"))
+			return
+		}
+		comments = append(comments, comment)
+	}
+	comments, next, err := pagination.Next(page, comments, func(comment model.Comment) []interface{} {
+		return []interface{}{comment.CreatedAt, comment.CommentID}
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get comments"))
+		return
+	}
+
+	items := make([]dto.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		items = append(items, toCommentResponse(comment))
+	}
+	c.JSON(http.StatusOK, dto.NewPage(items, next))
+}
+
+func CreateComment(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	text, ok := bindCommentText(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	task, board, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	mentions, err := commentMentions(ctx, firestoreClient, board, text, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get board members"))
+		return
+	}
+
+	now := time.Now()
+	comment := model.Comment{
+		CommentID: uuid.New().String(),
+		TaskID:    taskId,
+		BoardID:   task.BoardID,
+		UserID:    userId,
+		Text:      text,
+		Mentions:  mentions,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// บันทึกความคิดเห็นและการแจ้งเตือนผู้ถูก mention พร้อมกัน
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Create(services.CommentCollection(firestoreClient, taskId).Doc(comment.CommentID), comment); err != nil {
+			return err
+		}
+		return createMentionNotifications(firestoreClient, tx, comment, task, mentions)
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to create comment"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Comment created successfully",
+		"comment": toCommentResponse(comment),
+	})
+}
+
+// EditComment แก้ไขได้เฉพาะเจ้าของความคิดเห็นและภายใน commentEditWindow
+// แจ้งเตือนเฉพาะผู้ที่ถูก mention เพิ่มใหม่
+func EditComment(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+	commentId := c.Param("commentid")
+
+	text, ok := bindCommentText(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	task, board, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	mentions, err := commentMentions(ctx, firestoreClient, board, text, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get board members"))
+		return
+	}
+
+	docRef := services.CommentCollection(firestoreClient, taskId).Doc(commentId)
+	var updated model.Comment
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		comment, err := getCommentTx(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if comment.UserID != userId {
+			return errCommentForbidden
+		}
+		now := time.Now()
+		if now.Sub(comment.CreatedAt) > commentEditWindow {
+			return errEditWindowClosed
+		}
+
+		added, _ := diffIDs(comment.Mentions, mentions)
+
+		comment.Text = text
+		comment.Mentions = mentions
+		comment.Edited = true
+		comment.UpdatedAt = now
+		updated = *comment
+
+		if err := tx.Update(docRef, []firestore.Update{
+			{Path: "text", Value: text},
+			{Path: "mentions", Value: mentions},
+			{Path: "edited", Value: true},
+			{Path: "updatedat", Value: now},
+		}); err != nil {
+			return err
+		}
+		return createMentionNotifications(firestoreClient, tx, updated, task, added)
+	})
+	if err != nil {
+		respondCommentError(c, err, "failed to update comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment updated successfully",
+		"comment": toCommentResponse(updated),
+	})
+}
+
+// DeleteComment ลบได้โดยเจ้าของความคิดเห็นหรือเจ้าของบอร์ด
+func DeleteComment(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+	commentId := c.Param("commentid")
+
+	ctx := c.Request.Context()
+	_, board, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	docRef := services.CommentCollection(firestoreClient, taskId).Doc(commentId)
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		comment, err := getCommentTx(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if comment.UserID != userId && board.CreatedBy != userId {
+			return errCommentForbidden
+		}
+		return tx.Delete(docRef)
+	})
+	if err != nil {
+		respondCommentError(c, err, "failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Comment deleted successfully",
+		"commentid": commentId,
+	})
+}
+
+func bindCommentText(c *gin.Context) (string, bool) {
+	var req dto.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return "", false
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" || utf8.RuneCountInString(text) > maxCommentLength {
+		apperror.Abort(c, apperror.Invalid("Text must be between 1 and 2000 characters"))
+		return "", false
+	}
+	return text, true
+}
+
+// commentMentions หาสมาชิกบอร์ดที่ถูก mention โดยไม่นับผู้เขียนเอง
+func commentMentions(ctx context.Context, firestoreClient *firestore.Client, board *model.Board, text, authorID string) ([]string, error) {
+	if !strings.Contains(text, "@") {
+		return nil, nil
+	}
+
+	members, err := services.GetBoardMembers(ctx, firestoreClient, board)
+	if err != nil {
+		return nil, err
+	}
+
+	var mentions []string
+	for _, id := range services.ParseMentions(text, members) {
+		if id != authorID {
+			mentions = append(mentions, id)
+		}
+	}
+	return mentions, nil
+}
+
+func createMentionNotifications(firestoreClient *firestore.Client, tx *firestore.Transaction, comment model.Comment, task *model.Tasks, userIDs []string) error {
+	for _, id := range userIDs {
+		notification := services.NewUserNotification(id, services.NotificationMention, comment.UserID,
+			fmt.Sprintf("You were mentioned in a comment on %q", task.TaskName))
+		notification.BoardID = comment.BoardID
+		notification.TaskID = comment.TaskID
+		notification.CommentID = comment.CommentID
+		if err := tx.Create(services.UserNotificationCollection(firestoreClient).Doc(notification.NotificationID), notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getCommentTx(tx *firestore.Transaction, docRef *firestore.DocumentRef) (*model.Comment, error) {
+	docSnap, err := tx.Get(docRef)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errCommentNotFound
+		}
+		return nil, err
+	}
+
+	var comment model.Comment
+	if err := docSnap.DataTo(&comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func respondCommentError(c *gin.Context, err error, fallback string) {
+	apperror.Abort(c, apperror.OrInternal(err, fallback))
+}
+
+func toCommentResponse(comment model.Comment) dto.CommentResponse {
+	return dto.CommentResponse{
+		CommentID: comment.CommentID,
+		TaskID:    comment.TaskID,
+		UserID:    comment.UserID,
+		Text:      comment.Text,
+		Mentions:  dto.EmptyIfNil(comment.Mentions),
+		Edited:    comment.Edited,
+		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: comment.UpdatedAt.Format(time.RFC3339),
+	}
+}
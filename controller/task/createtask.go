@@ -1,10 +1,11 @@
 package task
 
 import (
-	"context"
+	"myapp/apperror"
 	"myapp/dto"
 	"myapp/middleware"
 	"myapp/model"
+	"myapp/search"
 	"myapp/services"
 	"net/http"
 	"time"
@@ -14,75 +15,100 @@ import (
 	"github.com/google/uuid"
 )
 
-func CreateTaskController(router *gin.Engine, firestoreClient *firestore.Client) {
+func CreateTaskController(router gin.IRouter, firestoreClient *firestore.Client, index search.Index) {
 
-	router.POST("/task", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
-		Createtask(c, firestoreClient)
+	router.POST("/tasks", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		Createtask(c, firestoreClient, index)
 	})
 }
 
-func Createtask(c *gin.Context, firestoreClient *firestore.Client) {
+func Createtask(c *gin.Context, firestoreClient *firestore.Client, index search.Index) {
 	userId := c.MustGet("userId").(string)
 	var taskReq dto.CreateTaskRequest
 	if err := c.ShouldBindJSON(&taskReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
 		return
 	}
 
 	// ค้นหาผู้ใช้จากฐานข้อมูล
-	ctx := context.Background()
-	_, err := services.GetUserDataByUserid(ctx, firestoreClient, userId)
+	ctx := c.Request.Context()
+	docSnap, err := services.GetUserDataByUserid(ctx, firestoreClient, userId)
 	if err != nil {
-		c.JSON(404, gin.H{"error": err.Error()})
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
 		return
 	}
+	var user model.User
+	if err := docSnap.DataTo(&user); err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
+		return
+	}
+	loc := services.UserLocation(&user)
 
-	taskid := uuid.New().String()
+	// แปลงวันเริ่มต้นและวันครบกำหนดตาม timezone ของผู้ใช้
+	startDate, err := services.ParseTaskTime(taskReq.StartDate, taskReq.AllDay, loc)
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid startdate format"))
+		return
+	}
+	dueDate, err := services.ParseTaskTime(taskReq.DueDate, taskReq.AllDay, loc)
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid duedate format"))
+		return
+	}
+	if startDate != nil && dueDate != nil && dueDate.Before(*startDate) {
+		apperror.Abort(c, apperror.Invalid("duedate must not be before startdate"))
+		return
+	}
+
+	// ตรวจสอบสิทธิ์ในบอร์ดก่อนบันทึกข้อมูลใดๆ
+	board, err := services.GetBoardForUser(ctx, firestoreClient, taskReq.BoardID, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+	column, err := services.ResolveStatus(board, taskReq.Status)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
 
+	now := time.Now()
 	newtask := model.Tasks{
-		TaskID:      taskid,
+		TaskID:      uuid.New().String(),
 		BoardID:     taskReq.BoardID,
 		TaskName:    taskReq.TaskName,
 		Description: taskReq.Description,
-		Status:      taskReq.Status,
+		Status:      column.ColumnID,
 		Priority:    taskReq.Priority,
 		CreatedBy:   userId,
-		UpdatedAt:   time.Now(),
+		StartDate:   startDate,
+		DueDate:     dueDate,
+		AllDay:      taskReq.AllDay,
+		CompletedAt: services.CompletionTime(false, column.Done, nil, now),
+		CreatedAt:   now,
 	}
+	item := services.NewTask{Task: newtask}
 
-	// บันทึก Task ลง collection Tasks ด้วย document ID = taskid
-	_, err = firestoreClient.Collection("Tasks").Doc(taskid).Set(ctx, newtask)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create task"})
-		return
-	}
-
-	// ตรวจสอบว่ามี Reminder หรือไม่
+	// ตรวจสอบ Reminder ให้เรียบร้อยก่อนบันทึก task
 	if taskReq.Reminder != nil {
-		notidicationid := uuid.New().String()
-
-		// แปลง due_date จาก string เป็น *time.Time (pointer)
-		var dueDate *time.Time
-		if taskReq.Reminder.DueDate != "" {
-			// สมมติว่า format เป็น "2006-01-02T15:04:05Z07:00" หรือตาม format ที่คุณใช้
-			parsedDate, err := time.Parse(time.RFC3339, taskReq.Reminder.DueDate)
-			if err != nil {
-				// ถ้า parse ไม่ได้ ให้ใช้ format อื่น หรือ handle error ตามต้องการ
-				c.JSON(400, gin.H{"error": "Invalid due_date format"})
-				return
-			}
-			dueDate = &parsedDate // ใช้ address ของ parsedDate
+		// แปลง due_date จาก string เป็น *time.Time ถ้าไม่ระบุจะใช้วันครบกำหนดของ task
+		reminderDueDate, err := services.ParseTaskTime(taskReq.Reminder.DueDate, false, loc)
+		if err != nil {
+			apperror.Abort(c, apperror.Invalid("Invalid due_date format"))
+			return
+		}
+		if reminderDueDate == nil {
+			reminderDueDate = services.TaskDueAt(&newtask, loc)
 		}
 
 		// แปลง before_due_date จาก string เป็น *time.Time (ถ้ามี)
 		var beforeDueDate *time.Time
-		if taskReq.Reminder.BeforeDueDate != nil && *taskReq.Reminder.BeforeDueDate != "" {
-			parsedBeforeDueDate, err := time.Parse(time.RFC3339, *taskReq.Reminder.BeforeDueDate)
+		if taskReq.Reminder.BeforeDueDate != nil {
+			beforeDueDate, err = services.ParseTaskTime(*taskReq.Reminder.BeforeDueDate, false, loc)
 			if err != nil {
-				c.JSON(400, gin.H{"error": "Invalid before_due_date format"})
+				apperror.Abort(c, apperror.Invalid("Invalid before_due_date format"))
 				return
 			}
-			beforeDueDate = &parsedBeforeDueDate
 		}
 
 		// แปลง recurring_pattern เป็น *string
@@ -92,25 +118,26 @@ func Createtask(c *gin.Context, firestoreClient *firestore.Client) {
 		}
 
 		newnotification := model.Notification{
-			NotificationID:   notidicationid,
-			TaskID:           taskid,
-			DueDate:          dueDate,
+			DueDate:          reminderDueDate,
 			BeforeDueDate:    beforeDueDate,
 			RecurringPattern: recurringPattern,
 			Send:             "0", // default value สำหรับ Send status
-			Updatedat:        time.Now(),
 		}
-
-		// บันทึก Notification ลง collection Notifications ด้วย document ID = notidicationid
-		_, err = firestoreClient.Collection("NotificationTasks").Doc(notidicationid).Set(ctx, newnotification)
-		if err != nil {
-			// ถ้าบันทึก notification ไม่สำเร็จ อาจต้อง rollback task ที่สร้างไปแล้ว
-			// ลบ task ที่สร้างไปแล้ว
-			firestoreClient.Collection("Tasks").Doc(taskid).Delete(ctx)
-			c.JSON(500, gin.H{"error": "Failed to create notification"})
+		if err := services.ValidateReminder(&newtask, &newnotification); err != nil {
+			apperror.Abort(c, err)
 			return
 		}
+		item.Reminders = append(item.Reminders, newnotification)
+	}
+
+	// บันทึก Task และ Notification พร้อมกันใน transaction เดียว
+	items := []services.NewTask{item}
+	if err := services.CreateTasks(ctx, firestoreClient, items); err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to create task"))
+		return
 	}
+	services.IndexTasks(ctx, index, items[0].Task)
+	taskid := newtask.TaskID
 
 	response := gin.H{
 		"message": "Task created successfully",
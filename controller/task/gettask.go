@@ -0,0 +1,94 @@
+package task
+
+import (
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTaskController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.GET("/tasks/:taskid", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		GetTask(c, firestoreClient)
+	})
+}
+
+func GetTask(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	ctx := c.Request.Context()
+	task, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	checklists, err := services.GetChecklists(ctx, firestoreClient, taskId)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get checklists"))
+		return
+	}
+
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, buildTaskResponse(task, checklists, loc))
+}
+
+func buildTaskResponse(task *model.Tasks, checklists []model.Checklist, loc *time.Location) dto.TaskResponse {
+	items := make([]dto.ChecklistResponse, 0, len(checklists))
+	for _, item := range checklists {
+		items = append(items, toChecklistResponse(item))
+	}
+
+	// task เก่าที่สร้างก่อนมีฟิลด์ createdat จะไม่มีค่า
+	var createdAt *time.Time
+	if !task.CreatedAt.IsZero() {
+		createdAt = &task.CreatedAt
+	}
+
+	return dto.TaskResponse{
+		TaskID:      task.TaskID,
+		BoardID:     task.BoardID,
+		TaskName:    task.TaskName,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		CreatedBy:   task.CreatedBy,
+		StartDate:   services.FormatTaskTime(task.StartDate, task.AllDay, loc),
+		DueDate:     services.FormatTaskTime(task.DueDate, task.AllDay, loc),
+		AllDay:      task.AllDay,
+		Position:    task.Position,
+		CompletedAt: services.FormatTaskTime(task.CompletedAt, false, loc),
+		CreatedAt:   services.FormatTaskTime(createdAt, false, loc),
+		UpdatedAt:   task.UpdatedAt.In(loc).Format(time.RFC3339),
+		Labels:      dto.EmptyIfNil(task.Labels),
+		Assignees:   dto.EmptyIfNil(task.Assignees),
+		Checklists:  items,
+		Progress:    services.ChecklistProgress(checklists),
+	}
+}
+
+func toChecklistResponse(item model.Checklist) dto.ChecklistResponse {
+	return dto.ChecklistResponse{
+		ChecklistID: item.ChecklistID,
+		Title:       item.Title,
+		Status:      item.Status,
+		Order:       item.Order,
+	}
+}
+
+// respondTaskError แปลง error จาก services เป็น HTTP response
+func respondTaskError(c *gin.Context, err error) {
+	apperror.Abort(c, apperror.OrInternal(err, "failed to get task"))
+}
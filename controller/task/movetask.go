@@ -0,0 +1,185 @@
+package task
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/services"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errColumnTooLarge = apperror.New(apperror.CodeColumnTooLarge)
+	errTaskMovedBoard = apperror.New(apperror.CodeTaskConflict).WithDetail("task was moved to another board")
+)
+
+func MoveTaskController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.PUT("/tasks/:taskid/move", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		MoveTask(c, firestoreClient)
+	})
+}
+
+// MoveTask ย้าย task ไปยังคอลัมน์และตำแหน่งใหม่ใน request เดียว (drag and drop)
+// position ใหม่อยู่กึ่งกลางระหว่าง task ข้างเคียง ถ้าช่องว่างไม่พอจะจัดเรียงทั้งคอลัมน์ใหม่
+func MoveTask(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	var req dto.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+	if req.AfterID == taskId || req.BeforeID == taskId {
+		apperror.Abort(c, apperror.Invalid("afterid and beforeid must refer to another task"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	_, board, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+	column, _, ok := services.FindColumn(services.BoardColumns(board), req.ColumnID)
+	if !ok {
+		apperror.Abort(c, services.ErrUnknownStatus)
+		return
+	}
+
+	tasksRef := firestoreClient.Collection("Tasks")
+	var position float64
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(tasksRef.Doc(taskId))
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return services.ErrTaskNotFound
+			}
+			return err
+		}
+		var task model.Tasks
+		if err := docSnap.DataTo(&task); err != nil {
+			return err
+		}
+		if task.BoardID != board.BoardID {
+			return errTaskMovedBoard
+		}
+
+		docs, err := tx.Documents(tasksRef.Where("boardid", "==", board.BoardID).Where("status", "==", column.ColumnID)).GetAll()
+		if err != nil {
+			return err
+		}
+		siblings := make([]model.Tasks, 0, len(docs))
+		for _, doc := range docs {
+			var sibling model.Tasks
+			if err := doc.DataTo(&sibling); err != nil {
+				return err
+			}
+			if sibling.TaskID != taskId {
+				siblings = append(siblings, sibling)
+			}
+		}
+		services.SortByPosition(siblings)
+
+		index, err := moveIndex(siblings, req.AfterID, req.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		var prev, next *float64
+		if index > 0 {
+			prev = &siblings[index-1].Position
+		}
+		if index < len(siblings) {
+			next = &siblings[index].Position
+		}
+
+		now := time.Now()
+		var fits bool
+		position, fits = services.PositionBetween(prev, next)
+		if !fits {
+			// ช่องว่างไม่พอ จัด position ของทั้งคอลัมน์ใหม่ให้ห่างกันเท่าๆ กัน
+			if len(siblings)+1 > maxBatchWrites {
+				return errColumnTooLarge
+			}
+			for i, sibling := range siblings {
+				slot := i
+				if i >= index {
+					slot++
+				}
+				newPosition := float64(slot+1) * services.PositionStep
+				if sibling.Position == newPosition {
+					continue
+				}
+				if err := tx.Update(tasksRef.Doc(sibling.TaskID), []firestore.Update{
+					{Path: "position", Value: newPosition},
+				}); err != nil {
+					return err
+				}
+			}
+			position = float64(index+1) * services.PositionStep
+		}
+
+		updates := []firestore.Update{
+			{Path: "position", Value: position},
+			{Path: "updatedat", Value: now},
+		}
+		if task.Status != column.ColumnID {
+			updates = append(updates,
+				firestore.Update{Path: "status", Value: column.ColumnID},
+				timeUpdate("completedat", services.CompletionTime(services.IsTaskCompleted(&task), column.Done, task.CompletedAt, now)),
+			)
+		}
+		return tx.Update(tasksRef.Doc(taskId), updates)
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to move task"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Task moved successfully",
+		"taskID":   taskId,
+		"columnid": column.ColumnID,
+		"position": position,
+	})
+}
+
+// moveIndex ตำแหน่งที่จะแทรก task ในรายการ siblings ที่เรียงแล้ว
+func moveIndex(siblings []model.Tasks, afterID, beforeID string) (int, error) {
+	find := func(id string) int {
+		for i, sibling := range siblings {
+			if sibling.TaskID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	switch {
+	case afterID != "":
+		i := find(afterID)
+		if i < 0 {
+			return 0, apperror.Invalid("afterid is not a task in the target column")
+		}
+		if beforeID != "" && (i+1 >= len(siblings) || siblings[i+1].TaskID != beforeID) {
+			return 0, apperror.Invalid("afterid and beforeid are not adjacent")
+		}
+		return i + 1, nil
+	case beforeID != "":
+		i := find(beforeID)
+		if i < 0 {
+			return 0, apperror.Invalid("beforeid is not a task in the target column")
+		}
+		return i, nil
+	}
+	return len(siblings), nil
+}
@@ -0,0 +1,80 @@
+package task
+
+import (
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/services"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+var errUnknownLabel = apperror.New(apperror.CodeLabelUnknown)
+
+func TaskLabelController(router gin.IRouter, firestoreClient *firestore.Client) {
+	router.PUT("/tasks/:taskid/labels", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		SetTaskLabels(c, firestoreClient)
+	})
+}
+
+// SetTaskLabels แทนที่ label ทั้งหมดของ task ด้วยรายการที่ส่งมา (ส่ง [] เพื่อล้าง)
+func SetTaskLabels(c *gin.Context, firestoreClient *firestore.Client) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	var req dto.SetTaskLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
+		return
+	}
+	labelIDs := services.ParseIDList(req.LabelIDs)
+	if len(labelIDs) > services.MaxLabelsPerTask {
+		apperror.Abort(c, apperror.Invalid("A task can have at most 10 labels"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	task, err := services.GetTaskForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	labels, err := services.GetLabels(ctx, firestoreClient, task.BoardID)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to get labels"))
+		return
+	}
+	known := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		known[label.LabelID] = true
+	}
+	for _, id := range labelIDs {
+		if !known[id] {
+			apperror.Abort(c, errUnknownLabel.WithField("labelid", id))
+			return
+		}
+	}
+
+	var value interface{} = labelIDs
+	if len(labelIDs) == 0 {
+		value = firestore.Delete
+	}
+	_, err = firestoreClient.Collection("Tasks").Doc(taskId).Update(ctx, []firestore.Update{
+		{Path: "labels", Value: value},
+		{Path: "updatedat", Value: time.Now()},
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to update labels"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task labels updated successfully",
+		"taskID":  taskId,
+		"labels":  dto.EmptyIfNil(labelIDs),
+	})
+}
@@ -0,0 +1,205 @@
+package task
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/dto"
+	"myapp/middleware"
+	"myapp/model"
+	"myapp/search"
+	"myapp/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func UpdateTaskController(router gin.IRouter, firestoreClient *firestore.Client, index search.Index) {
+	router.PUT("/tasks/:taskid", middleware.AccessTokenMiddleware(), func(c *gin.Context) {
+		UpdateTask(c, firestoreClient, index)
+	})
+}
+
+func UpdateTask(c *gin.Context, firestoreClient *firestore.Client, index search.Index) {
+	userId := c.MustGet("userId").(string)
+	taskId := c.Param("taskid")
+
+	var req dto.UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.Invalid(dto.BindErrorMessage(err)))
+		return
+	}
+
+	ctx := c.Request.Context()
+	task, board, err := services.GetTaskAndBoardForUser(ctx, firestoreClient, taskId, userId)
+	if err != nil {
+		respondTaskError(c, err)
+		return
+	}
+
+	loc, err := services.GetUserLocation(ctx, firestoreClient, userId)
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to get user"))
+		return
+	}
+	// reminder ของ task แบบทั้งวันคำนวณจากเที่ยงคืนตาม timezone ของผู้สร้าง task
+	creatorLoc := loc
+	if task.CreatedBy != "" && task.CreatedBy != userId {
+		if creatorLoc, err = services.GetUserLocation(ctx, firestoreClient, task.CreatedBy); err != nil {
+			creatorLoc = loc
+		}
+	}
+
+	// อ่านข้อมูลล่าสุดและแก้ไขใน transaction เดียว เพื่อให้ completedat คำนวณจาก status ปัจจุบันเสมอ
+	docRef := firestoreClient.Collection("Tasks").Doc(taskId)
+	err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return services.ErrTaskNotFound
+			}
+			return err
+		}
+		var current model.Tasks
+		if err := docSnap.DataTo(&current); err != nil {
+			return err
+		}
+
+		reminderDocs, err := tx.Documents(firestoreClient.Collection("NotificationTasks").Where("taskid", "==", taskId)).GetAll()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		oldDueAt := services.TaskDueAt(&current, creatorLoc)
+		updates, err := buildTaskUpdates(&current, &req, services.BoardColumns(board), loc, now)
+		if err != nil {
+			return err
+		}
+
+		// เมื่อวันครบกำหนดเปลี่ยน reminder ที่ผูกกับวันเดิมต้องเลื่อนตามใน transaction เดียวกัน
+		for _, doc := range reminderDocs {
+			var reminder model.Notification
+			if err := doc.DataTo(&reminder); err != nil {
+				return err
+			}
+			if !services.ShiftReminder(&reminder, oldDueAt, services.TaskDueAt(&current, creatorLoc), now) {
+				continue
+			}
+			if err := services.ValidateReminder(&current, &reminder); err != nil {
+				return err
+			}
+			if err := tx.Update(doc.Ref, []firestore.Update{
+				timeUpdate("duedate", reminder.DueDate),
+				timeUpdate("beforeduedate", reminder.BeforeDueDate),
+				{Path: "send", Value: reminder.Send},
+				{Path: "updatedat", Value: now},
+			}); err != nil {
+				return err
+			}
+		}
+		return tx.Update(docRef, updates)
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.OrInternal(err, "failed to update task"))
+		return
+	}
+	if req.TaskName != nil || req.Description != nil {
+		services.ReindexTasks(ctx, firestoreClient, index, taskId)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task updated successfully",
+		"taskID":  taskId,
+	})
+}
+
+// buildTaskUpdates สร้างรายการ field ที่ต้องแก้ไขจากค่าปัจจุบันของ task
+// และปรับค่าวันที่ใน task ให้เป็นค่าใหม่เพื่อใช้ตรวจสอบ reminder ต่อ
+func buildTaskUpdates(task *model.Tasks, req *dto.UpdateTaskRequest, columns []model.BoardColumn, loc *time.Location, now time.Time) ([]firestore.Update, error) {
+	var updates []firestore.Update
+
+	if req.TaskName != nil {
+		name := strings.TrimSpace(*req.TaskName)
+		if name == "" {
+			return nil, apperror.Invalid("taskname must not be empty")
+		}
+		updates = append(updates, firestore.Update{Path: "taskname", Value: name})
+	}
+	if req.Description != nil {
+		updates = append(updates, firestore.Update{Path: "description", Value: *req.Description})
+	}
+	if req.Priority != nil {
+		updates = append(updates, firestore.Update{Path: "priority", Value: *req.Priority})
+	}
+
+	// เมื่อย้ายเข้าคอลัมน์ done จะบันทึกเวลาที่ทำเสร็จให้อัตโนมัติ และ task จะไปอยู่ท้ายคอลัมน์ใหม่
+	if req.Status != nil && *req.Status != task.Status {
+		column, _, ok := services.FindColumn(columns, *req.Status)
+		if !ok {
+			return nil, services.ErrUnknownStatus
+		}
+		updates = append(updates,
+			firestore.Update{Path: "status", Value: column.ColumnID},
+			firestore.Update{Path: "position", Value: services.InitialPosition(now)},
+			timeUpdate("completedat", services.CompletionTime(services.IsTaskCompleted(task), column.Done, task.CompletedAt, now)),
+		)
+	}
+
+	allDay, wasAllDay := task.AllDay, task.AllDay
+	if req.AllDay != nil {
+		allDay = *req.AllDay
+		updates = append(updates, firestore.Update{Path: "allday", Value: allDay})
+	}
+
+	startDate, err := resolveTaskTime(req.StartDate, task.StartDate, allDay, wasAllDay, loc)
+	if err != nil {
+		return nil, apperror.Invalid("Invalid startdate format")
+	}
+	dueDate, err := resolveTaskTime(req.DueDate, task.DueDate, allDay, wasAllDay, loc)
+	if err != nil {
+		return nil, apperror.Invalid("Invalid duedate format")
+	}
+	if startDate != nil && dueDate != nil && dueDate.Before(*startDate) {
+		return nil, apperror.Invalid("duedate must not be before startdate")
+	}
+	if req.StartDate != nil || req.AllDay != nil {
+		updates = append(updates, timeUpdate("startdate", startDate))
+	}
+	if req.DueDate != nil || req.AllDay != nil {
+		updates = append(updates, timeUpdate("duedate", dueDate))
+	}
+	task.StartDate, task.DueDate, task.AllDay = startDate, dueDate, allDay
+
+	if len(updates) == 0 {
+		return nil, apperror.Invalid("No data to update")
+	}
+	return append(updates, firestore.Update{Path: "updatedat", Value: now}), nil
+}
+
+// resolveTaskTime คืนค่าวันเวลาใหม่ของ task
+// ถ้าไม่ได้ส่งค่ามาแต่มีการเปลี่ยน allday จะแปลงค่าเดิมระหว่างวันที่ล้วนกับเที่ยงคืนตาม timezone ของผู้ใช้
+func resolveTaskTime(value *string, current *time.Time, allDay, wasAllDay bool, loc *time.Location) (*time.Time, error) {
+	if value != nil {
+		return services.ParseTaskTime(*value, allDay, loc)
+	}
+	if current != nil && allDay != wasAllDay {
+		day := services.AllDayIn(*current, loc)
+		if allDay {
+			day = services.AllDayDate(*current, loc)
+		}
+		return &day, nil
+	}
+	return current, nil
+}
+
+func timeUpdate(path string, t *time.Time) firestore.Update {
+	if t == nil {
+		return firestore.Update{Path: path, Value: firestore.Delete}
+	}
+	return firestore.Update{Path: path, Value: *t}
+}
@@ -4,87 +4,127 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"myapp/apperror"
+	"myapp/config"
 	"myapp/dto"
 	"myapp/middleware"
 	"myapp/model"
+	"myapp/pagination"
+	"myapp/services"
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"cloud.google.com/go/firestore"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
-	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func UserController(router *gin.Engine, firestoreClient *firestore.Client) {
-	routes := router.Group("/user", middleware.AccessTokenMiddleware())
+const (
+	minUserSearchLength  = 3
+	maxUserSearchLength  = 100
+	maxUserSearchResults = 10
+)
+
+func UserController(router gin.IRouter, firestoreClient *firestore.Client) {
+	routes := router.Group("/users", middleware.AccessTokenMiddleware())
 	{
-		routes.POST("/search", func(c *gin.Context) {
+		// จำกัดจำนวนครั้งต่อผู้ใช้ ป้องกันการไล่หาอีเมลผู้ใช้
+		routes.POST("/search", middleware.RateLimit(config.RateLimitUserSearch, middleware.ByUser), func(c *gin.Context) {
 			SearchUser(c, firestoreClient)
 		})
-		routes.PUT("/profile", func(c *gin.Context) {
+		routes.PUT("/me", func(c *gin.Context) {
 			UpdateProfileUser(c, firestoreClient)
 		})
-		routes.DELETE("/account", func(c *gin.Context) {
+		routes.DELETE("/me", func(c *gin.Context) {
 			DeleteUser(c, firestoreClient)
 		})
 	}
 }
 
+// SearchUser ค้นหาผู้ใช้ที่จะเชิญเข้าบอร์ด
+// query ที่มี "@" ต้องเป็นอีเมลแบบตรงทั้งหมด ไม่เช่นนั้นค้นจากต้นชื่อ
+// คืนเฉพาะผู้ใช้ที่ยืนยันอีเมลและยังใช้งานอยู่ และเปิดเผยเฉพาะข้อมูลสาธารณะ
 func SearchUser(c *gin.Context, fb *firestore.Client) {
-	var emailReq dto.SearchEmailRequest
-	if err := c.ShouldBindJSON(&emailReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+	userId := c.MustGet("userId").(string)
+
+	var req dto.SearchUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
-	ctx := context.Background()
-	searchText := emailReq.Email
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		query = strings.TrimSpace(req.Email)
+	}
+	if n := utf8.RuneCountInString(query); n < minUserSearchLength || n > maxUserSearchLength {
+		apperror.Abort(c, apperror.Invalid("Query must be between 3 and 100 characters"))
+		return
+	}
 
-	iter := fb.Collection("Users").
-		Where("email", ">=", searchText).
-		Where("email", "<=", searchText+"\uf8ff").
-		Documents(ctx)
+	page, err := pagination.New("users:"+query, req.PageToken, req.Limit, maxUserSearchResults, maxUserSearchResults)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
 
-	var userResponses []dto.UserResponse
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
+	// ต้องมี composite index: active + verify + name
+	users := fb.Collection("Users").
+		Where("active", "==", model.AccountActive).
+		Where("verify", "==", model.Verified)
+	byEmail := strings.Contains(query, "@")
+	if byEmail {
+		emails := []string{query}
+		if lower := strings.ToLower(query); lower != query {
+			emails = append(emails, lower)
 		}
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+		users = users.Where("email", "in", emails)
+	} else {
+		users = users.Where("name", ">=", query).Where("name", "<=", query+"\uf8ff").OrderBy("name", firestore.Asc)
+	}
+	users = users.OrderBy(firestore.DocumentID, firestore.Asc)
+
+	ctx := c.Request.Context()
+	docs, err := page.Query(users).Documents(ctx).GetAll()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to search users"))
+		return
+	}
+	docs, next, err := pagination.Next(page, docs, func(doc *firestore.DocumentSnapshot) []interface{} {
+		if byEmail {
+			return []interface{}{doc.Ref.ID}
 		}
+		name, _ := doc.Data()["name"].(string)
+		return []interface{}{name, doc.Ref.ID}
+	})
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "failed to search users"))
+		return
+	}
 
+	// ตัดตัวผู้ค้นหาออกหลังแบ่งหน้า หน้านั้นจึงอาจมีน้อยกว่า limit หนึ่งรายการ
+	results := make([]dto.PublicUserResponse, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Ref.ID == userId {
+			continue
+		}
 		var user model.User
 		if err := doc.DataTo(&user); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse user data"})
+			apperror.Abort(c, apperror.Internal(err, "failed to parse user data"))
 			return
 		}
-
-		userResp := dto.UserResponse{
-			UserID:    user.UserID,
-			Name:      user.Name,
-			Email:     user.Email,
-			Profile:   user.Profile,
-			Role:      user.Role,
-			IsVerify:  user.Verify,
-			IsActive:  user.Active,
-			CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		}
-
-		userResponses = append(userResponses, userResp)
-	}
-
-	if userResponses == nil {
-		userResponses = []dto.UserResponse{}
+		results = append(results, dto.PublicUserResponse{
+			UserID:  doc.Ref.ID,
+			Name:    user.Name,
+			Profile: user.Profile,
+		})
 	}
 
-	c.JSON(http.StatusOK, userResponses)
+	c.JSON(http.StatusOK, dto.NewPage(results, next))
 }
 
 func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
@@ -92,13 +132,13 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 
 	var updateProfile dto.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&updateProfile); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		apperror.Abort(c, apperror.New(apperror.CodeInvalidRequest))
 		return
 	}
 
 	// Validate if there's anything to update
-	if updateProfile.Name == "" && updateProfile.Password == "" && updateProfile.Profile == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No data to update"})
+	if updateProfile.Name == "" && updateProfile.Password == "" && updateProfile.Profile == "" && updateProfile.Timezone == "" {
+		apperror.Abort(c, apperror.Invalid("No data to update"))
 		return
 	}
 
@@ -106,7 +146,7 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 	if updateProfile.Name != "" {
 		updateProfile.Name = strings.TrimSpace(updateProfile.Name)
 		if len(updateProfile.Name) < 2 || len(updateProfile.Name) > 100 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Name must be between 2 and 100 characters"})
+			apperror.Abort(c, apperror.Invalid("Name must be between 2 and 100 characters"))
 			return
 		}
 	}
@@ -114,12 +154,20 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 	if updateProfile.Profile != "" {
 		updateProfile.Profile = strings.TrimSpace(updateProfile.Profile)
 		if len(updateProfile.Profile) > 500 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile description must not exceed 500 characters"})
+			apperror.Abort(c, apperror.Invalid("Profile description must not exceed 500 characters"))
+			return
+		}
+	}
+
+	if updateProfile.Timezone != "" {
+		updateProfile.Timezone = strings.TrimSpace(updateProfile.Timezone)
+		if _, err := time.LoadLocation(updateProfile.Timezone); err != nil {
+			apperror.Abort(c, apperror.Invalid("Invalid timezone"))
 			return
 		}
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 
 	// Reference to the user document
 	userDocRef := firestoreClient.Collection("Users").Doc(userId)
@@ -134,13 +182,16 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 	if updateProfile.Profile != "" {
 		updateMap["profile"] = updateProfile.Profile
 	}
+	if updateProfile.Timezone != "" {
+		updateMap["timezone"] = updateProfile.Timezone
+	}
 
 	// Handle password hashing if password is provided
 	if updateProfile.Password != "" {
 		// Hash password synchronously
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(updateProfile.Password), bcrypt.DefaultCost)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+			apperror.Abort(c, apperror.Internal(err, "failed to process password"))
 			return
 		}
 		updateMap["password"] = string(hashedPassword)
@@ -155,14 +206,14 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 		userDoc, err := tx.Get(userDocRef)
 		if err != nil {
 			if status.Code(err) == codes.NotFound {
-				return errors.New("user not found")
+				return services.ErrUserNotFound
 			}
-			return errors.New("failed to retrieve user")
+			return fmt.Errorf("failed to retrieve user: %w", err)
 		}
 
 		// Verify document exists and has data
 		if !userDoc.Exists() {
-			return errors.New("user not found")
+			return services.ErrUserNotFound
 		}
 
 		// Create firestore updates only for fields that are being updated
@@ -180,15 +231,10 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 
 	// Handle transaction errors
 	if err != nil {
-		switch err.Error() {
-		case "user not found":
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		case "failed to process password":
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
-		case "failed to retrieve user":
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user profile"})
+		if errors.Is(err, services.ErrUserNotFound) {
+			apperror.Abort(c, err)
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "failed to update user profile"))
 		}
 		return
 	}
@@ -204,7 +250,7 @@ func UpdateProfileUser(c *gin.Context, firestoreClient *firestore.Client) {
 
 func DeleteUser(c *gin.Context, firestoreClient *firestore.Client) {
 	userId := c.MustGet("userId").(string)
-	ctx := context.Background()
+	ctx := c.Request.Context()
 
 	// Use channels for concurrent checking
 	type checkResult struct {
@@ -263,7 +309,7 @@ func DeleteUser(c *gin.Context, firestoreClient *firestore.Client) {
 	// Get result from goroutine
 	result := <-checkChan
 	if result.err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check user associations"})
+		apperror.Abort(c, apperror.Internal(result.err, "failed to check user associations"))
 		return
 	}
 
@@ -273,15 +319,15 @@ func DeleteUser(c *gin.Context, firestoreClient *firestore.Client) {
 	if result.hasAssociations {
 		// Deactivate user by updating active field
 		_, err := userDocRef.Update(ctx, []firestore.Update{
-			{Path: "active", Value: "2"}, // หรือ false หากเป็น boolean
+			{Path: "active", Value: model.AccountDeleted},
 		})
 		if err != nil {
 			// Check if document doesn't exist
 			if status.Code(err) == codes.NotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				apperror.Abort(c, apperror.New(apperror.CodeUserNotFound))
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate user"})
+			apperror.Abort(c, apperror.Internal(err, "failed to deactivate user"))
 			return
 		}
 
@@ -292,10 +338,10 @@ func DeleteUser(c *gin.Context, firestoreClient *firestore.Client) {
 		if err != nil {
 			// Check if document doesn't exist
 			if status.Code(err) == codes.NotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				apperror.Abort(c, apperror.New(apperror.CodeUserNotFound))
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+			apperror.Abort(c, apperror.Internal(err, "failed to delete user"))
 			return
 		}
 
@@ -0,0 +1,30 @@
+package dto
+
+import "myapp/model"
+
+type AgendaItem struct {
+	TaskID     string           `json:"taskid"`
+	BoardID    string           `json:"boardid"`
+	TaskName   string           `json:"taskname"`
+	Status     model.TaskStatus `json:"status"`
+	Priority   model.Priority   `json:"priority"`
+	DueDate    string           `json:"duedate"`
+	AllDay     bool             `json:"allday"`
+	Overdue    bool             `json:"overdue"`
+	Pattern    string           `json:"pattern,omitempty"`
+	Occurrence bool             `json:"occurrence"`
+	Labels     []string         `json:"labels"`
+}
+
+type AgendaDay struct {
+	Date  string       `json:"date"`
+	Items []AgendaItem `json:"items"`
+}
+
+type AgendaResponse struct {
+	Timezone string       `json:"timezone"`
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Overdue  []AgendaItem `json:"overdue"`
+	Days     []AgendaDay  `json:"days"`
+}
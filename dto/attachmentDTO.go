@@ -0,0 +1,11 @@
+package dto
+
+type AttachmentResponse struct {
+	AttachmentID string `json:"attachmentid"`
+	TaskID       string `json:"taskid"`
+	FileName     string `json:"filename"`
+	ContentType  string `json:"contenttype"`
+	Size         int64  `json:"size"`
+	UploadedBy   string `json:"uploadedby"`
+	CreatedAt    string `json:"createdat"`
+}
@@ -1,6 +1,92 @@
 package dto
 
+import (
+	"encoding/json"
+	"myapp/model"
+)
+
 type CreateBoardRequest struct {
-	BoardName string `json:"boardname" validate:"required"`
-	Is_group  string `json:"isgroup"`
+	BoardName string    `json:"boardname" binding:"required"`
+	Is_group  GroupFlag `json:"isgroup"`
+}
+
+// GroupFlag ค่า isgroup "0" = private, "1" = group (รับ 0/1 และ true/false ได้ด้วย)
+// ไม่ส่งมาถือเป็นบอร์ดส่วนตัว แต่ถ้าส่งค่าที่ไม่รู้จักมาจะถูกปฏิเสธ
+type GroupFlag string
+
+func (f GroupFlag) BoardType() model.BoardType {
+	if f == "1" {
+		return model.BoardGroup
+	}
+	return model.BoardPrivate
+}
+
+func (f *GroupFlag) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*f = "0"
+		if b {
+			*f = "1"
+		}
+		return nil
+	}
+	value, err := model.UnmarshalEnum(data, "isgroup", func(v string) bool { return v == "0" || v == "1" })
+	if err != nil {
+		return err
+	}
+	*f = GroupFlag(value)
+	return nil
+}
+
+type ImportRowResult struct {
+	Line     int      `json:"line"`
+	TaskName string   `json:"taskname"`
+	DueDate  string   `json:"duedate,omitempty"`
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+type ImportResponse struct {
+	DryRun   bool              `json:"dryrun"`
+	Total    int               `json:"total"`
+	Invalid  int               `json:"invalid"`
+	Imported int               `json:"imported"`
+	TaskIDs  []string          `json:"taskids,omitempty"`
+	Rows     []ImportRowResult `json:"rows"`
+}
+
+// LabelRequest ใช้ทั้งสร้างและแก้ไข label, color ต้องอยู่ในรูป "#RRGGBB"
+type LabelRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color" binding:"required"`
+}
+
+type LabelResponse struct {
+	LabelID string `json:"labelid"`
+	BoardID string `json:"boardid"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+}
+
+// BoardColumn columnid ว่างหมายถึงคอลัมน์ใหม่
+type BoardColumn struct {
+	ColumnID model.TaskStatus `json:"columnid"`
+	Name     string           `json:"name"`
+	Done     bool             `json:"done"`
+}
+
+// UpdateColumnsRequest ลำดับคอลัมน์ทั้งหมดของบอร์ด แทนที่ค่าเดิมทั้งหมด
+type UpdateColumnsRequest struct {
+	Columns []BoardColumn `json:"columns" binding:"required"`
+}
+
+// BoardResponse บอร์ดในรายการบอร์ดของผู้ใช้ owner เป็นจริงเมื่อผู้ใช้เป็นผู้สร้างบอร์ด
+type BoardResponse struct {
+	BoardID   string          `json:"boardid"`
+	BoardName string          `json:"boardname"`
+	Type      model.BoardType `json:"type"`
+	DeepLink  string          `json:"deep_link,omitempty"`
+	CreatedBy string          `json:"createdby"`
+	CreatedAt string          `json:"createdat"`
+	Owner     bool            `json:"owner"`
 }
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"myapp/model"
+)
+
+func TestCreateBoardRequestGroupFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    model.BoardType
+		wantErr bool
+	}{
+		{name: "omitted defaults to private", body: `{"boardname":"b"}`, want: model.BoardPrivate},
+		{name: "null defaults to private", body: `{"boardname":"b","isgroup":null}`, want: model.BoardPrivate},
+		{name: "string zero", body: `{"boardname":"b","isgroup":"0"}`, want: model.BoardPrivate},
+		{name: "string one", body: `{"boardname":"b","isgroup":"1"}`, want: model.BoardGroup},
+		{name: "number one", body: `{"boardname":"b","isgroup":1}`, want: model.BoardGroup},
+		{name: "true", body: `{"boardname":"b","isgroup":true}`, want: model.BoardGroup},
+		{name: "false", body: `{"boardname":"b","isgroup":false}`, want: model.BoardPrivate},
+		{name: "unknown value", body: `{"boardname":"b","isgroup":"2"}`, wantErr: true},
+		{name: "empty string", body: `{"boardname":"b","isgroup":""}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req CreateBoardRequest
+			err := json.Unmarshal([]byte(tt.body), &req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && req.Is_group.BoardType() != tt.want {
+				t.Fatalf("board type = %q, want %q", req.Is_group.BoardType(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package dto
+
+type CommentRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+type CommentResponse struct {
+	CommentID string   `json:"commentid"`
+	TaskID    string   `json:"taskid"`
+	UserID    string   `json:"userid"`
+	Text      string   `json:"text"`
+	Mentions  []string `json:"mentions"`
+	Edited    bool     `json:"edited"`
+	CreatedAt string   `json:"createdat"`
+	UpdatedAt string   `json:"updatedat"`
+}
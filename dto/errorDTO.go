@@ -0,0 +1,11 @@
+package dto
+
+// ErrorResponse รูปแบบ error เดียวของทุก endpoint
+// error เป็นข้อความตามภาษาของผู้ใช้ ใช้ชื่อ field เดิมเพื่อให้ client รุ่นเก่ายังแสดงข้อความได้
+type ErrorResponse struct {
+	Code      string                 `json:"code"`
+	Error     string                 `json:"error"`
+	Detail    string                 `json:"detail,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// UserNotificationResponse การแจ้งเตือนในแอป read เป็น "0" = ยังไม่อ่าน, "1" = อ่านแล้ว
+type UserNotificationResponse struct {
+	NotificationID string `json:"notificationid"`
+	Type           string `json:"type"`
+	ActorID        string `json:"actorid"`
+	BoardID        string `json:"boardid,omitempty"`
+	TaskID         string `json:"taskid,omitempty"`
+	CommentID      string `json:"commentid,omitempty"`
+	Message        string `json:"message"`
+	Read           string `json:"read"`
+	CreatedAt      string `json:"createdat"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// Page รูปแบบเดียวของทุกรายการที่แบ่งหน้า nextPageToken ว่างคือหน้าสุดท้าย
+// ส่งค่าเดิมกลับมาใน pageToken เพื่อดึงหน้าถัดไป
+type Page[T any] struct {
+	Items         []T    `json:"items"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// NewPage คืน items เป็น array ว่างแทน null เมื่อไม่มีรายการ
+func NewPage[T any](items []T, nextPageToken string) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{Items: items, NextPageToken: nextPageToken}
+}
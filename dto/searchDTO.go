@@ -0,0 +1,10 @@
+package dto
+
+type SearchItem struct {
+	Type    string  `json:"type"` // "task" หรือ "board"
+	ID      string  `json:"id"`
+	BoardID string  `json:"boardid"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet,omitempty"`
+	Score   float64 `json:"score"`
+}
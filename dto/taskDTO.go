@@ -1,12 +1,31 @@
 package dto
 
+import (
+	"errors"
+	"myapp/model"
+)
+
 type CreateTaskRequest struct {
-	BoardID     string    `json:"boardid" binding:"required"`
-	TaskName    string    `json:"taskname" binding:"required"`
-	Description string    `json:"description"`
-	Status      string    `json:"status" binding:"required"`
-	Reminder    *Reminder `json:"reminder"`
-	Priority    string    `json:"priority"`
+	BoardID     string           `json:"boardid" binding:"required"`
+	TaskName    string           `json:"taskname" binding:"required"`
+	Description string           `json:"description"`
+	Status      model.TaskStatus `json:"status" binding:"required"`
+	Reminder    *Reminder        `json:"reminder"`
+	Priority    model.Priority   `json:"priority"`
+	StartDate   string           `json:"startdate"`
+	DueDate     string           `json:"duedate"`
+	AllDay      bool             `json:"allday"`
+}
+
+// UpdateTaskRequest ฟิลด์ที่เป็น nil จะไม่ถูกแก้ไข, ส่ง "" เพื่อล้างค่าวันที่
+type UpdateTaskRequest struct {
+	TaskName    *string           `json:"taskname"`
+	Description *string           `json:"description"`
+	Status      *model.TaskStatus `json:"status"`
+	Priority    *model.Priority   `json:"priority"`
+	StartDate   *string           `json:"startdate"`
+	DueDate     *string           `json:"duedate"`
+	AllDay      *bool             `json:"allday"`
 }
 
 type Reminder struct {
@@ -14,3 +33,116 @@ type Reminder struct {
 	BeforeDueDate    *string `json:"beforeduedate"`
 	RecurringPattern string  `json:"pattern,omitempty"`
 }
+
+type CreateChecklistRequest struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type ReorderChecklistRequest struct {
+	ChecklistIDs []string `json:"checklistids" binding:"required"`
+}
+
+type ChecklistResponse struct {
+	ChecklistID string `json:"checklistid"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Order       int    `json:"order"`
+}
+
+type TaskResponse struct {
+	TaskID      string              `json:"taskid"`
+	BoardID     string              `json:"boardid"`
+	TaskName    string              `json:"taskname"`
+	Description string              `json:"description"`
+	Status      model.TaskStatus    `json:"status"`
+	Priority    model.Priority      `json:"priority"`
+	CreatedBy   string              `json:"createdby"`
+	StartDate   string              `json:"startdate,omitempty"`
+	DueDate     string              `json:"duedate,omitempty"`
+	AllDay      bool                `json:"allday"`
+	Position    float64             `json:"position"`
+	CompletedAt string              `json:"completedat,omitempty"`
+	CreatedAt   string              `json:"createdat,omitempty"`
+	UpdatedAt   string              `json:"updatedat"`
+	Labels      []string            `json:"labels"`
+	Assignees   []string            `json:"assignees"`
+	Checklists  []ChecklistResponse `json:"checklists"`
+	Progress    int                 `json:"progress"`
+}
+
+// MoveTaskRequest ย้าย task ไปคอลัมน์ columnid โดยวางต่อจาก afterid หรือก่อน beforeid
+// ถ้าไม่ส่งทั้งสองค่าจะวางท้ายคอลัมน์
+type MoveTaskRequest struct {
+	ColumnID model.TaskStatus `json:"columnid" binding:"required"`
+	AfterID  string           `json:"afterid"`
+	BeforeID string           `json:"beforeid"`
+}
+
+type SetAssigneesRequest struct {
+	UserIDs []string `json:"userids"`
+}
+
+type SetTaskLabelsRequest struct {
+	LabelIDs []string `json:"labelids"`
+}
+
+type TaskSummary struct {
+	TaskID    string           `json:"taskid"`
+	BoardID   string           `json:"boardid"`
+	TaskName  string           `json:"taskname"`
+	Status    model.TaskStatus `json:"status"`
+	Priority  model.Priority   `json:"priority"`
+	DueDate   string           `json:"duedate,omitempty"`
+	AllDay    bool             `json:"allday"`
+	Position  float64          `json:"position"`
+	Labels    []string         `json:"labels"`
+	Assignees []string         `json:"assignees"`
+}
+
+type BatchTaskRequest struct {
+	Operations []BatchTaskOperation `json:"operations" binding:"required"`
+}
+
+// BatchTaskOperation op: "create", "update", "move" หรือ "delete"
+type BatchTaskOperation struct {
+	Op            string            `json:"op"`
+	TaskID        string            `json:"taskid"`
+	BoardID       string            `json:"boardid"`
+	TargetBoardID string            `json:"targetboardid"`
+	TaskName      string            `json:"taskname"`
+	Description   string            `json:"description"`
+	Status        *model.TaskStatus `json:"status"`
+	Priority      *model.Priority   `json:"priority"`
+}
+
+type BatchTaskResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	TaskID string `json:"taskid,omitempty"`
+	OK     bool   `json:"ok"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchTaskResponse struct {
+	Success bool              `json:"success"`
+	Results []BatchTaskResult `json:"results"`
+}
+
+// EmptyIfNil ให้ slice ที่ไม่มีค่าถูกส่งออกเป็น [] แทน null
+func EmptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// BindErrorMessage ข้อความ error ของ request ที่ bind ไม่ผ่าน
+// ถ้าเป็นค่า enum ที่ไม่ถูกต้องจะบอกว่า field ไหนผิด
+func BindErrorMessage(err error) string {
+	var enumErr *model.InvalidEnumError
+	if errors.As(err, &enumErr) {
+		return enumErr.Error()
+	}
+	return "Invalid input"
+}
@@ -1,25 +1,42 @@
 package dto
 
+import "myapp/model"
+
 type UserResponse struct {
-	UserID    string `json:"user_id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Profile   string `json:"profile"`
-	Role      string `json:"role"`
-	IsVerify  string `json:"is_verify"`
-	IsActive  string `json:"is_active"`
-	CreatedAt string `json:"created_at"`
+	UserID    string              `json:"user_id"`
+	Name      string              `json:"name"`
+	Email     string              `json:"email"`
+	Profile   string              `json:"profile"`
+	Role      string              `json:"role"`
+	IsVerify  model.VerifyStatus  `json:"is_verify"`
+	IsActive  model.AccountStatus `json:"is_active"`
+	Timezone  string              `json:"timezone"`
+	CreatedAt string              `json:"created_at"`
 }
 
 type EmailRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
-type SearchEmailRequest struct {
-	Email string `json:"email"`
+
+// SearchUserRequest ค้นหาผู้ใช้เพื่อเชิญเข้าบอร์ด query เป็นอีเมลแบบตรงทั้งหมดหรือต้นชื่อ
+// ยังรับ email สำหรับ client เดิม
+type SearchUserRequest struct {
+	Query     string `json:"query"`
+	Email     string `json:"email"`
+	Limit     int    `json:"limit"`
+	PageToken string `json:"pageToken"`
+}
+
+// PublicUserResponse ข้อมูลผู้ใช้ที่เปิดเผยต่อผู้ใช้อื่นได้
+type PublicUserResponse struct {
+	UserID  string `json:"user_id"`
+	Name    string `json:"name"`
+	Profile string `json:"profile"`
 }
 
 type UpdateProfileRequest struct {
 	Name     string `json:"name"`
 	Password string `json:"password"`
 	Profile  string `json:"profile"`
+	Timezone string `json:"timezone"`
 }
@@ -0,0 +1,127 @@
+// Package health ตรวจสถานะของส่วนที่ระบบพึ่งพา (Firestore, SMTP, งานเบื้องหลัง) สำหรับ readiness probe
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // ส่วนที่ไม่จำเป็นต่อการรับคำขอล้มเหลว
+	StatusDown     = "down"
+)
+
+// Check คืน error เมื่อส่วนนั้นใช้งานไม่ได้ ต้องเคารพ deadline ของ ctx
+type Check func(ctx context.Context) error
+
+type ComponentStatus struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+type Report struct {
+	Status     string                     `json:"status"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+type component struct {
+	name     string
+	check    Check
+	critical bool
+}
+
+// Checker รันทุก check พร้อมกัน แต่ละตัวมีเวลาไม่เกิน timeout
+// ผลลัพธ์ถูกเก็บไว้ cacheTTL เพื่อไม่ให้ probe ที่ถี่ๆ ไปกระทบ Firestore หรือ SMTP
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	components []component
+
+	mu     sync.Mutex
+	cached *Report
+}
+
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Register เพิ่มส่วนที่ถ้าล้มเหลวระบบจะไม่พร้อมรับคำขอ ต้องเรียกก่อนเริ่ม server
+func (c *Checker) Register(name string, check Check) {
+	c.components = append(c.components, component{name: name, check: check, critical: true})
+}
+
+// RegisterOptional เพิ่มส่วนที่ถ้าล้มเหลวจะรายงานเป็น degraded แต่ยังรับคำขอได้
+func (c *Checker) RegisterOptional(name string, check Check) {
+	c.components = append(c.components, component{name: name, check: check})
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cached != nil && time.Since(c.cached.CheckedAt) < c.cacheTTL {
+		return *c.cached
+	}
+
+	report := Report{
+		Status:     StatusOK,
+		CheckedAt:  time.Now(),
+		Components: make(map[string]ComponentStatus, len(c.components)),
+	}
+	results := make([]ComponentStatus, len(c.components))
+
+	var wg sync.WaitGroup
+	for i, comp := range c.components {
+		wg.Add(1)
+		go func(i int, comp component) {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, comp)
+		}(i, comp)
+	}
+	wg.Wait()
+
+	for i, comp := range c.components {
+		result := results[i]
+		report.Components[comp.name] = result
+		if result.Status == StatusOK {
+			continue
+		}
+		if comp.critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	c.cached = &report
+	return report
+}
+
+func (c *Checker) runCheck(ctx context.Context, comp component) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- comp.check(ctx) }()
+
+	// check ที่ไม่เคารพ ctx จะไม่ทำให้ probe ค้าง
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := ComponentStatus{Status: StatusOK, Critical: comp.critical, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,93 @@
+// Package logging ตั้งค่า slog ของทั้งแอป: ระดับ log, รูปแบบ JSON/text,
+// request id จาก context และการปิดบังข้อมูลส่วนตัวก่อนเขียน log
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"myapp/config"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup สร้าง logger ตามค่าตั้งค่าแล้วตั้งเป็น slog.Default
+// log.Printf ที่ยังเหลืออยู่จะถูกส่งผ่าน handler นี้ด้วย จึงถูกปิดบังข้อมูลเหมือนกัน
+func Setup(cfg config.LogConfig) (*slog.Logger, error) {
+	logger, err := New(os.Stdout, cfg)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+func New(w io.Writer, cfg config.LogConfig) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// ParseLevel รับ debug, info, warn หรือ error (ค่าว่างคือ info)
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", value)
+	}
+	return level, nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID แนบ request id ไปกับ context เพื่อให้ log ใน services อ้างถึงคำขอเดียวกันได้
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler เพิ่ม request_id และ trace_id ให้ทุก record ที่เขียนด้วย slog.*Context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._\-]+`)
+)
+
+// sensitiveKeys ค่าของ key ที่มีคำเหล่านี้จะไม่ถูกเขียนลง log เลย
+var sensitiveKeys = []string{"password", "secret", "token", "otp", "authorization", "cookie"}
+
+// emailKeys ค่าของ key เหล่านี้เป็นอีเมล เก็บไว้แค่บางส่วนพอให้ไล่ปัญหาได้
+var emailKeys = map[string]bool{"email": true, "to": true, "recipient": true}
+
+// Redact ปิดบังอีเมลและ token ที่ปนอยู่ในข้อความ เช่นข้อความ error
+func Redact(s string) string {
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	return emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+}
+
+// MaskEmail เหลือตัวอักษรแรกของชื่อและโดเมนไว้ เช่น j***@example.com
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(attr.Key))
+	for _, word := range sensitiveKeys {
+		if strings.Contains(key, word) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		if emailKeys[key] {
+			return slog.String(attr.Key, MaskEmail(value.String()))
+		}
+		return slog.String(attr.Key, Redact(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(err.Error()))
+		}
+	}
+	return attr
+}
@@ -0,0 +1,109 @@
+// Package mailer ส่งอีเมลแบบ asynchronous ผ่านคิว ไม่ให้ request หรืองานเบื้องหลังต้องรอ SMTP
+package mailer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"myapp/metrics"
+	"sync"
+)
+
+var (
+	ErrQueueFull   = errors.New("mail queue is full")
+	ErrQueueClosed = errors.New("mail queue is closed")
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string // HTML
+}
+
+// SendFunc ส่งอีเมลหนึ่งฉบับ เช่น services.SendingEmail
+type SendFunc func(msg Message) error
+
+// Queue กระจายอีเมลให้ worker ส่ง ถ้าส่งไม่สำเร็จจะบันทึก log ไว้และไม่ส่งซ้ำ
+type Queue struct {
+	send     SendFunc
+	messages chan Message
+	wg       sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewQueue(send SendFunc, workers, size int) *Queue {
+	q := &Queue{send: send, messages: make(chan Message, size)}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for msg := range q.messages {
+		err := q.send(msg)
+		metrics.MailSent(err)
+		if err != nil {
+			slog.Error("failed to send email", "to", msg.To, "subject", msg.Subject, "error", err)
+		}
+	}
+}
+
+// Enqueue ไม่รอเมื่อคิวเต็ม ผู้เรียกตัดสินใจเองว่าจะทิ้งหรือลองใหม่
+func (q *Queue) Enqueue(msg Message) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	select {
+	case q.messages <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Len จำนวนอีเมลที่รอส่งอยู่ในคิว
+func (q *Queue) Len() int {
+	return len(q.messages)
+}
+
+// Check ใช้กับ readiness probe คิวต้องยังเปิดอยู่และไม่เต็ม
+func (q *Queue) Check(ctx context.Context) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if len(q.messages) == cap(q.messages) {
+		return ErrQueueFull
+	}
+	return nil
+}
+
+// Close หยุดรับอีเมลใหม่แล้วรอให้ส่งอีเมลที่ค้างในคิวจนหมด หรือจนกว่า ctx จะหมดเวลา
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.messages)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
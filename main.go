@@ -1,13 +1,29 @@
 package main
 
 import (
+	"log"
+	"log/slog"
+	"myapp/config"
 	"myapp/connection"
+	"myapp/logging"
+	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// ค่าตั้งค่าที่ขาดหรือผิดต้องหยุดตั้งแต่เริ่มต้น ไม่ใช่ไปพังตอนใช้งาน
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := logging.Setup(cfg.Log); err != nil {
+		log.Fatal(err)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
-	// go scheduler.StartScheduler()
-	connection.StartServer()
+	if err := connection.StartServer(cfg); err != nil {
+		slog.Error("server exited", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// collection ที่ใช้เป็น label เมื่อระบุจากคำขอไม่ได้ หรือคำขอเดียวแตะหลาย collection
+const (
+	collectionNone  = "-"
+	collectionMixed = "mixed"
+)
+
+// FirestoreUnaryInterceptor นับ RPC แบบ unary เช่น Commit, BatchWrite และ BeginTransaction
+func FirestoreUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		ObserveFirestore(requestCollection(req), path.Base(method), status.Code(err), time.Since(start))
+		return err
+	}
+}
+
+// FirestoreStreamInterceptor นับ RPC แบบ stream เช่น RunQuery และ BatchGetDocuments (ที่ Doc.Get ใช้)
+// จะบันทึกเมื่อ stream จบ ส่วน stream ที่ไม่จบเอง เช่น Listen จะไม่ถูกนับ
+func FirestoreStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			ObserveFirestore(collectionNone, path.Base(method), status.Code(err), time.Since(start))
+			return nil, err
+		}
+		return &observedStream{ClientStream: stream, operation: path.Base(method), start: start, collection: collectionNone}, nil
+	}
+}
+
+type observedStream struct {
+	grpc.ClientStream
+	operation  string
+	start      time.Time
+	collection string
+	once       sync.Once
+}
+
+func (s *observedStream) SendMsg(m interface{}) error {
+	if s.collection == collectionNone {
+		s.collection = requestCollection(m)
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *observedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			code := status.Code(err)
+			if errors.Is(err, io.EOF) {
+				code = codes.OK
+			}
+			ObserveFirestore(s.collection, s.operation, code, time.Since(s.start))
+		})
+	}
+	return err
+}
+
+// requestCollection ชื่อ collection ชั้นในสุดที่คำขอแตะ (subcollection ใช้ชื่อของตัวเอง)
+func requestCollection(req interface{}) string {
+	var names []string
+	switch r := req.(type) {
+	case *firestorepb.CommitRequest:
+		names = writeNames(r.GetWrites())
+	case *firestorepb.BatchWriteRequest:
+		names = writeNames(r.GetWrites())
+	case *firestorepb.BatchGetDocumentsRequest:
+		names = r.GetDocuments()
+	case *firestorepb.GetDocumentRequest:
+		names = []string{r.GetName()}
+	case *firestorepb.UpdateDocumentRequest:
+		names = []string{r.GetDocument().GetName()}
+	case *firestorepb.DeleteDocumentRequest:
+		names = []string{r.GetName()}
+	case *firestorepb.ListDocumentsRequest:
+		return orNone(r.GetCollectionId())
+	case *firestorepb.RunQueryRequest:
+		return queryCollection(r.GetStructuredQuery())
+	case *firestorepb.RunAggregationQueryRequest:
+		return queryCollection(r.GetStructuredAggregationQuery().GetStructuredQuery())
+	}
+
+	collection := ""
+	for _, name := range names {
+		c := documentCollection(name)
+		switch {
+		case collection == "":
+			collection = c
+		case c != collection:
+			return collectionMixed
+		}
+	}
+	return orNone(collection)
+}
+
+func writeNames(writes []*firestorepb.Write) []string {
+	names := make([]string, 0, len(writes))
+	for _, w := range writes {
+		switch {
+		case w.GetUpdate() != nil:
+			names = append(names, w.GetUpdate().GetName())
+		case w.GetDelete() != "":
+			names = append(names, w.GetDelete())
+		case w.GetTransform() != nil:
+			names = append(names, w.GetTransform().GetDocument())
+		}
+	}
+	return names
+}
+
+func queryCollection(query *firestorepb.StructuredQuery) string {
+	if from := query.GetFrom(); len(from) > 0 {
+		return orNone(from[0].GetCollectionId())
+	}
+	return collectionNone
+}
+
+// documentCollection แปลง projects/p/databases/d/documents/Users/u1 เป็น Users
+func documentCollection(name string) string {
+	_, rest, ok := strings.Cut(name, "/documents/")
+	if !ok {
+		return ""
+	}
+	segments := strings.Split(rest, "/")
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[len(segments)-2]
+}
+
+func orNone(collection string) string {
+	if collection == "" {
+		return collectionNone
+	}
+	return collection
+}
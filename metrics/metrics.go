@@ -0,0 +1,166 @@
+// Package metrics เก็บตัวชี้วัดของแอปในรูปแบบ Prometheus และเปิดให้อ่านผ่าน /metrics
+// label ทุกตัวต้องมีค่าจำกัด ห้ามใส่ user id, email หรือ path จริง
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// ผลลัพธ์ของการเข้าสู่ระบบ
+const (
+	SignInPassword = "password"
+	SignInGoogle   = "google"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultError   = "error"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	firestoreOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "firestore_operations_total",
+		Help: "Firestore RPCs by collection, operation and gRPC status code.",
+	}, []string{"collection", "operation", "code"})
+
+	firestoreDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "firestore_operation_duration_seconds",
+		Help:    "Firestore RPC latency by collection and operation.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"collection", "operation"})
+
+	otpSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "otp_sent_total",
+		Help: "OTP emails sent by purpose (verify or resetpassword).",
+	}, []string{"purpose"})
+
+	otpBlocked = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "otp_blocked_total",
+		Help: "OTP requests rejected because the email is or has just been blocked.",
+	}, []string{"purpose", "reason"})
+
+	signIns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "signin_attempts_total",
+		Help: "Sign-in attempts by method and result.",
+	}, []string{"method", "result", "reason"})
+
+	remindersDelivered = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "reminders_delivered_total",
+		Help: "Task reminders delivered as in-app notifications.",
+	})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_job_runs_total",
+		Help: "Background job runs by job name and result.",
+	}, []string{"job", "result"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scheduler_job_duration_seconds",
+		Help:    "Background job run time by job name.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"job"})
+
+	mailSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mail_sent_total",
+		Help: "Emails sent by the mail queue workers by result.",
+	}, []string{"result"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests rejected with 429 by rate limit policy.",
+	}, []string{"policy"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		firestoreOperations, firestoreDuration,
+		otpSent, otpBlocked, signIns,
+		remindersDelivered, jobRuns, jobDuration, mailSent,
+		rateLimited,
+	)
+}
+
+// Handler ตอบในรูปแบบ text exposition ของ Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveHTTP route ต้องเป็น route template ของ gin เช่น /task/:taskid
+func ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+func ObserveFirestore(collection, operation string, code codes.Code, elapsed time.Duration) {
+	firestoreOperations.WithLabelValues(collection, operation, code.String()).Inc()
+	firestoreDuration.WithLabelValues(collection, operation).Observe(elapsed.Seconds())
+}
+
+func OTPSent(purpose string) {
+	otpSent.WithLabelValues(purpose).Inc()
+}
+
+// OTPBlocked reason เป็น "blocked" (ถูกบล็อกอยู่แล้ว) หรือ "limit" (เพิ่งถูกบล็อกเพราะขอเกินกำหนด)
+func OTPBlocked(purpose, reason string) {
+	otpBlocked.WithLabelValues(purpose, reason).Inc()
+}
+
+// SignIn reason ว่างได้เมื่อสำเร็จ เช่น invalid_password, inactive, unverified
+func SignIn(method, result, reason string) {
+	signIns.WithLabelValues(method, result, reason).Inc()
+}
+
+func RemindersDelivered(n int) {
+	remindersDelivered.Add(float64(n))
+}
+
+func ObserveJob(name string, err error, elapsed time.Duration) {
+	jobRuns.WithLabelValues(name, result(err)).Inc()
+	jobDuration.WithLabelValues(name).Observe(elapsed.Seconds())
+}
+
+func MailSent(err error) {
+	mailSent.WithLabelValues(result(err)).Inc()
+}
+
+// RateLimited policy เป็นชื่อกลุ่มของการจำกัด เช่น auth ไม่ใช่ key ที่นับ
+func RateLimited(policy string) {
+	rateLimited.WithLabelValues(policy).Inc()
+}
+
+// MailQueueDepth ลงทะเบียน gauge จำนวนอีเมลที่รอส่ง เรียกครั้งเดียวตอนสร้างคิว
+func MailQueueDepth(depth func() int) {
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "mail_queue_depth",
+		Help: "Emails waiting in the mail queue.",
+	}, func() float64 { return float64(depth()) }))
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
@@ -1,20 +1,36 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
-	"net/http"
-	"os"
+	"myapp/apperror"
+	"myapp/config"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var jwtConfig config.JWTConfig
+
+// Configure ตั้งค่า secret ของ JWT จาก config ต้องเรียกครั้งเดียวก่อนเริ่ม server
+func Configure(cfg config.JWTConfig) {
+	jwtConfig = cfg
+}
+
+// signingKey ไม่ยอมตรวจ token ด้วย key ว่าง ถ้ายังไม่ได้เรียก Configure
+func signingKey(secret string) (interface{}, error) {
+	if secret == "" {
+		return nil, errors.New("jwt secret is not configured")
+	}
+	return []byte(secret), nil
+}
+
 func AccessTokenMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.Request.Header.Get("Authorization")
 		if header == "" {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Authorization header is missing"})
+			apperror.Abort(c, apperror.New(apperror.CodeAuthTokenMissing))
 			return
 		}
 
@@ -24,12 +40,11 @@ func AccessTokenMiddleware() gin.HandlerFunc {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
-			hmacSampleSecret := []byte(os.Getenv("JWT_SECRET_KEY"))
-			return hmacSampleSecret, nil
+			return signingKey(jwtConfig.AccessSecret)
 		})
 
 		if err != nil {
-			c.AbortWithStatusJSON(403, gin.H{"error": "Token is expired or invalid: " + err.Error()})
+			apperror.Abort(c, apperror.Wrap(apperror.CodeAuthTokenInvalid, err))
 			return
 		}
 
@@ -41,13 +56,13 @@ func AccessTokenMiddleware() gin.HandlerFunc {
 			if userID, ok := claims["userId"].(string); ok {
 				c.Set("userId", userID)
 			} else {
-				c.AbortWithStatusJSON(401, gin.H{"error": "Invalid userId in token claims"})
+				apperror.Abort(c, apperror.New(apperror.CodeAuthTokenInvalid).WithDetail("userId claim is missing"))
 				return
 			}
 
 			c.Next()
 		} else {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid token claims"})
+			apperror.Abort(c, apperror.New(apperror.CodeAuthTokenInvalid))
 			return
 		}
 	}
@@ -57,20 +72,20 @@ func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		claimsValue, exists := c.Get("claims")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Claims not found"})
+			apperror.Abort(c, apperror.New(apperror.CodeUnauthorized))
 			return
 		}
 
 		claims, ok := claimsValue.(jwt.MapClaims)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims format"})
+			apperror.Abort(c, apperror.New(apperror.CodeUnauthorized))
 			return
 		}
 
 		// แก้ไขให้ตรวจสอบ field "Role" แทน "role" เพื่อให้สอดคล้องกับการสร้าง token
 		role, ok := claims["Role"].(string)
 		if !ok || role != "admin" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			apperror.Abort(c, apperror.New(apperror.CodeAuthAdminRequired))
 			return
 		}
 
@@ -83,34 +98,30 @@ func RefreshTokenMiddleware() gin.HandlerFunc {
 		// รับ refresh token จาก Header
 		authHeader := c.Request.Header.Get("Authorization")
 		if authHeader == "" {
-			c.JSON(401, gin.H{"error": "Refresh token is missing"})
-			c.Abort()
+			apperror.Abort(c, apperror.New(apperror.CodeAuthTokenMissing))
 			return
 		}
 
 		// ตรวจสอบรูปแบบของ token
 		bearerToken := strings.Split(authHeader, " ")
 		if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
-			c.JSON(401, gin.H{"error": "Invalid token format"})
-			c.Abort()
+			apperror.Abort(c, apperror.New(apperror.CodeAuthRefreshInvalid).WithDetail("expected a Bearer token"))
 			return
 		}
 
 		refreshToken := bearerToken[1]
 
 		// Decode และตรวจสอบ token โดยใช้ JWT key
-		hmacSampleSecret := []byte(os.Getenv("JWT_REFRESH_SECRET_KEY"))
 		token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
 			// ตรวจสอบว่า token ใช้ algorithm HMAC
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
-			return hmacSampleSecret, nil
+			return signingKey(jwtConfig.RefreshSecret)
 		})
 
 		if err != nil {
-			c.JSON(403, gin.H{"error": "Invalid refresh token: " + err.Error()})
-			c.Abort()
+			apperror.Abort(c, apperror.Wrap(apperror.CodeAuthTokenInvalid, err))
 			return
 		}
 
@@ -124,8 +135,7 @@ func RefreshTokenMiddleware() gin.HandlerFunc {
 
 			// ตรวจสอบ field "UserID" (ให้สอดคล้องกับการสร้าง refresh token)
 			if userID, found = claims["userId"].(string); !found {
-				c.JSON(401, gin.H{"error": "Invalid token claims: UserID not found"})
-				c.Abort()
+				apperror.Abort(c, apperror.New(apperror.CodeAuthRefreshInvalid))
 				return
 			}
 
@@ -136,8 +146,7 @@ func RefreshTokenMiddleware() gin.HandlerFunc {
 			// ดำเนินการต่อไปยัง handler
 			c.Next()
 		} else {
-			c.JSON(401, gin.H{"error": "Invalid refresh token claims"})
-			c.Abort()
+			apperror.Abort(c, apperror.New(apperror.CodeAuthRefreshInvalid))
 			return
 		}
 	}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"myapp/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler ตอบ error ที่ handler ส่งมาด้วย apperror.Abort ในรูปแบบเดียวกันทั้งหมด
+// สาเหตุจริงอยู่ใน c.Errors ซึ่ง RequestLogger บันทึกลง log ส่วน client เห็นแค่ code และข้อความกลางๆ
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		appErr := apperror.From(c.Errors.Last().Err)
+		c.AbortWithStatusJSON(apperror.Status(appErr.Code), apperror.Body(c, appErr))
+	}
+}
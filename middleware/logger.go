@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"myapp/apperror"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger เขียน log หนึ่งบรรทัดต่อคำขอ ใช้ route template แทน path จริง
+// เพื่อไม่ให้ secret ใน path (เช่น calendar feed) หรือ query string ไปอยู่ใน log
+func RequestLogger(skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		if skip[c.FullPath()] {
+			return
+		}
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if c.FullPath() == "" {
+			attrs = append(attrs, slog.String("path", c.Request.URL.Path))
+		}
+		if userID := c.GetString("userId"); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery แทน gin.Recovery เพื่อให้ panic ถูกบันทึกผ่าน slog พร้อม request id
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		slog.ErrorContext(c.Request.Context(), "panic recovered",
+			"panic", recovered,
+			"stack", string(debug.Stack()),
+		)
+		apperror.Respond(c, apperror.New(apperror.CodeInternal))
+	})
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"myapp/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics นับคำขอและเวลาตอบตาม route template คำขอที่ไม่ตรง route ไหนรวมไว้ที่ "unmatched"
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
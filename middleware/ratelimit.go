@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"myapp/apperror"
+	"myapp/config"
+	"myapp/metrics"
+	"myapp/ratelimit"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	rateLimitConfig config.RateLimitConfig
+	rateLimitStore  ratelimit.Store = ratelimit.NewMemoryStore()
+)
+
+// ConfigureRateLimit ตั้งค่ากฎและที่เก็บถัง ต้องเรียกครั้งเดียวก่อนเริ่ม server
+// ถ้าไม่เรียกจะไม่จำกัดคำขอเลย
+func ConfigureRateLimit(cfg config.RateLimitConfig, store ratelimit.Store) {
+	rateLimitConfig = cfg
+	rateLimitStore = store
+}
+
+// RateKey คืน key ที่ใช้นับคำขอ ค่าว่างคือไม่นับคำขอนั้น
+type RateKey func(c *gin.Context) string
+
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser ต้องใช้หลัง AccessTokenMiddleware เพราะนับตาม userId ใน context
+func ByUser(c *gin.Context) string {
+	if userID := c.GetString("userId"); userID != "" {
+		return "user:" + userID
+	}
+	return ""
+}
+
+// maxPeekBody อ่าน body ได้ไม่เกินเท่านี้เพื่อหาอีเมล ส่วนที่เหลือยังส่งต่อให้ handler ครบ
+const maxPeekBody = 64 << 10
+
+// ByEmail นับตาม field email ใน JSON body โดยไม่ทำให้ handler อ่าน body ไม่ได้
+// เก็บเป็น hash เพื่อไม่ให้อีเมลไปอยู่ใน store ที่ใช้ร่วมกัน
+func ByEmail(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	peeked, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPeekBody))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), c.Request.Body), c.Request.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(peeked, &body) != nil {
+		return ""
+	}
+	email := strings.ToLower(strings.TrimSpace(body.Email))
+	if email == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(email))
+	return "email:" + hex.EncodeToString(sum[:16])
+}
+
+// RateLimit จำกัดคำขอตามกฎชื่อ policy ใน config.RateLimitConfig โดยนับแยกตาม key
+// เกินกำหนดจะตอบ 429 พร้อม Retry-After ถ้า store ใช้งานไม่ได้จะปล่อยคำขอผ่าน เพื่อไม่ให้ทั้งแอปล่มตาม
+func RateLimit(policy string, key RateKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := rateLimitConfig.Rules[policy]
+		if !rateLimitConfig.Enabled || !ok {
+			c.Next()
+			return
+		}
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		limit := ratelimit.Limit{PerMinute: rule.PerMinute, Burst: rule.Burst}
+		result, err := rateLimitStore.Take(c.Request.Context(), policy+":"+k, limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "rate limit store failed", "policy", policy, "error", err)
+			c.Next()
+			return
+		}
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			metrics.RateLimited(policy)
+			apperror.Abort(c, apperror.New(apperror.CodeRateLimited).WithField("policy", policy))
+			return
+		}
+		c.Next()
+	}
+}
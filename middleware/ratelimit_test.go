@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"myapp/config"
+	"myapp/dto"
+	"myapp/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeStore คืนผลที่กำหนดไว้ทุกครั้ง
+type fakeStore struct {
+	result ratelimit.Result
+	err    error
+}
+
+func (f *fakeStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return f.result, f.err
+}
+
+func newRateLimitRouter(policy string, key RateKey) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/", RateLimit(policy, key), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestRateLimitResponse(t *testing.T) {
+	enabled := config.RateLimitConfig{
+		Enabled: true,
+		Rules:   map[string]config.RateLimitRule{"test": {PerMinute: 1, Burst: 1}},
+	}
+
+	tests := []struct {
+		name       string
+		cfg        config.RateLimitConfig
+		store      *fakeStore
+		wantStatus int
+		wantRetry  string
+	}{
+		{
+			name:       "allowed",
+			cfg:        enabled,
+			store:      &fakeStore{result: ratelimit.Result{Allowed: true}},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "denied rounds retry after up",
+			cfg:        enabled,
+			store:      &fakeStore{result: ratelimit.Result{RetryAfter: 1200 * time.Millisecond}},
+			wantStatus: http.StatusTooManyRequests,
+			wantRetry:  "2",
+		},
+		{
+			name:       "denied retry after is at least one second",
+			cfg:        enabled,
+			store:      &fakeStore{result: ratelimit.Result{RetryAfter: 10 * time.Millisecond}},
+			wantStatus: http.StatusTooManyRequests,
+			wantRetry:  "1",
+		},
+		{
+			name:       "store error fails open",
+			cfg:        enabled,
+			store:      &fakeStore{err: errors.New("store down")},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "disabled",
+			cfg:        config.RateLimitConfig{Rules: enabled.Rules},
+			store:      &fakeStore{},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ConfigureRateLimit(tt.cfg, tt.store)
+			defer ConfigureRateLimit(config.RateLimitConfig{}, ratelimit.NewMemoryStore())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			newRateLimitRouter("test", ByIP).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+			if tt.wantStatus != http.StatusTooManyRequests {
+				return
+			}
+			var body dto.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Code != "RATE_LIMITED" || body.Fields["policy"] != "test" {
+				t.Fatalf("body = %+v, want RATE_LIMITED with policy field", body)
+			}
+		})
+	}
+}
+
+func TestRateLimitWithMemoryStore(t *testing.T) {
+	ConfigureRateLimit(config.RateLimitConfig{
+		Enabled: true,
+		Rules:   map[string]config.RateLimitRule{"test": {PerMinute: 1, Burst: 2}},
+	}, ratelimit.NewMemoryStore())
+	defer ConfigureRateLimit(config.RateLimitConfig{}, ratelimit.NewMemoryStore())
+
+	router := newRateLimitRouter("test", ByIP)
+	want := []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests}
+	for i, status := range want {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+		if w.Code != status {
+			t.Fatalf("request %d: status = %d, want %d", i, w.Code, status)
+		}
+	}
+}
+
+func TestByEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		sameAs  string
+		wantKey bool
+	}{
+		{name: "email", body: `{"email":"a@example.com"}`, wantKey: true},
+		{name: "normalized", body: `{"email":" A@Example.com "}`, sameAs: `{"email":"a@example.com"}`, wantKey: true},
+		{name: "missing", body: `{"password":"x"}`},
+		{name: "not json", body: `email=a@example.com`},
+	}
+
+	keyOf := func(body string) (string, string) {
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		key := ByEmail(c)
+		rest, _ := io.ReadAll(c.Request.Body)
+		return key, string(rest)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, rest := keyOf(tt.body)
+			if rest != tt.body {
+				t.Fatalf("body after peek = %q, want %q", rest, tt.body)
+			}
+			if (key != "") != tt.wantKey {
+				t.Fatalf("key = %q, want key: %v", key, tt.wantKey)
+			}
+			if strings.Contains(key, "@") {
+				t.Fatalf("key %q leaks the email address", key)
+			}
+			if tt.sameAs != "" {
+				if other, _ := keyOf(tt.sameAs); other != key {
+					t.Fatalf("key = %q, want %q", key, other)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"myapp/logging"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID รับ id จาก proxy หรือ client เฉพาะที่สั้นและไม่มีอักขระแปลกๆ ที่จะทำให้ log เพี้ยน
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,64}$`)
+
+// RequestID ใช้ X-Request-ID ที่ส่งมา หรือสร้างใหม่ แล้วแนบไปกับ context ของคำขอ,
+// header ของ response และ body ของ error response ในชื่อ requestId
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set("requestId", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Writer = &errorBodyWriter{ResponseWriter: c.Writer, requestID: id}
+		c.Next()
+	}
+}
+
+// errorBodyWriter เพิ่ม requestId ลงใน JSON object ของ response ที่มีสถานะ 4xx/5xx
+// ผู้ใช้แจ้งปัญหาพร้อม id นี้ได้ และใช้ค้น log ของคำขอนั้น
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	requestID string
+}
+
+func (w *errorBodyWriter) Write(data []byte) (int, error) {
+	if w.Status() < 400 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(data)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil || body == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, ok := body["requestId"]; !ok {
+		body["requestId"] = w.requestID
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *errorBodyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Attachment struct {
+	AttachmentID string    `firestore:"attachmentid,omitempty"`
+	TaskID       string    `firestore:"taskid,omitempty"`
+	BoardID      string    `firestore:"boardid,omitempty"`
+	FileName     string    `firestore:"filename,omitempty"`
+	ContentType  string    `firestore:"contenttype,omitempty"`
+	Size         int64     `firestore:"size"`
+	StorageKey   string    `firestore:"storagekey,omitempty"` // key ของไฟล์ใน blobstore
+	UploadedBy   string    `firestore:"uploadedby,omitempty"`
+	CreatedAt    time.Time `firestore:"createdat,omitempty"`
+}
@@ -5,9 +5,11 @@ import "time"
 type Board struct {
 	BoardID   string    `firestore:"boardid,omitempty"`
 	BoardName string    `firestore:"boardname,omitempty"`
-	BoardType string    `firestore:"type,omitempty"`
+	BoardType BoardType `firestore:"type,omitempty"`
 	DeepLink  string    `firestore:"link,omitempty"`
 	CreatedAt time.Time `firestore:"createdat,omitempty"`
 	CreatedBy string    `firestore:"createdby,omitempty"`
 	UpdatedAt time.Time `firestore:"updatedat,omitempty"`
+	// Columns ว่างหมายถึงใช้คอลัมน์เริ่มต้น (ดู services.BoardColumns)
+	Columns []BoardColumn `firestore:"columns,omitempty"`
 }
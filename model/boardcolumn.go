@@ -0,0 +1,9 @@
+package model
+
+// BoardColumn คอลัมน์สถานะของบอร์ด ลำดับของคอลัมน์คือลำดับใน Board.Columns
+// Tasks.Status เก็บ ColumnID ของคอลัมน์ที่ task อยู่
+type BoardColumn struct {
+	ColumnID TaskStatus `firestore:"columnid"`
+	Name     string     `firestore:"name"`
+	Done     bool       `firestore:"done"` // task ในคอลัมน์นี้ถือว่าทำเสร็จแล้ว
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type BoardUser struct {
+	BoardUserID string    `firestore:"boarduserid,omitempty"`
+	BoardID     string    `firestore:"boardid,omitempty"`
+	UserID      string    `firestore:"userid,omitempty"`
+	AddedAt     time.Time `firestore:"addedat,omitempty"`
+}
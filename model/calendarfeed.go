@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type CalendarFeed struct {
+	UserID    string    `firestore:"userid,omitempty"`
+	Secret    string    `firestore:"secret,omitempty"`
+	CreatedAt time.Time `firestore:"createdat,omitempty"`
+	UpdatedAt time.Time `firestore:"updatedat,omitempty"`
+}
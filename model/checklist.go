@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type Checklist struct {
+	ChecklistID string    `firestore:"checklistid,omitempty"`
+	TaskID      string    `firestore:"taskid,omitempty"`
+	Title       string    `firestore:"title,omitempty"`
+	Status      string    `firestore:"status,omitempty"` // "0" = not done, "1" = done
+	Order       int       `firestore:"order"`
+	CreatedBy   string    `firestore:"createdby,omitempty"`
+	CreatedAt   time.Time `firestore:"createdat,omitempty"`
+	UpdatedAt   time.Time `firestore:"updatedat,omitempty"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Comment struct {
+	CommentID string    `firestore:"commentid,omitempty"`
+	TaskID    string    `firestore:"taskid,omitempty"`
+	BoardID   string    `firestore:"boardid,omitempty"`
+	UserID    string    `firestore:"userid,omitempty"`
+	Text      string    `firestore:"text,omitempty"`
+	Mentions  []string  `firestore:"mentions,omitempty"` // userid ของผู้ที่ถูก @mention
+	Edited    bool      `firestore:"edited"`
+	CreatedAt time.Time `firestore:"createdat,omitempty"`
+	UpdatedAt time.Time `firestore:"updatedat,omitempty"`
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// InvalidEnumError ค่าที่ส่งมาไม่อยู่ในรายการที่รองรับ
+type InvalidEnumError struct {
+	Field string
+	Value string
+}
+
+func (e *InvalidEnumError) Error() string {
+	return fmt.Sprintf("invalid %s %q", e.Field, e.Value)
+}
+
+// UnmarshalEnum รับได้ทั้ง string และตัวเลข (เช่น 1 หรือ "1") แล้วตรวจสอบด้วย valid
+func UnmarshalEnum(data []byte, field string, valid func(string) bool) (string, error) {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return "", &InvalidEnumError{Field: field, Value: string(data)}
+		}
+		value = n.String()
+	}
+	if !valid(value) {
+		return "", &InvalidEnumError{Field: field, Value: value}
+	}
+	return value, nil
+}
+
+// TaskStatus คือ columnid ของบอร์ด ค่าด้านล่างเป็น id ของคอลัมน์เริ่มต้น
+// ความถูกต้องขึ้นกับคอลัมน์ของแต่ละบอร์ด (ดู services.ResolveStatus)
+type TaskStatus string
+
+const (
+	StatusPending    TaskStatus = "0"
+	StatusInProgress TaskStatus = "1"
+	StatusCompleted  TaskStatus = "2"
+)
+
+// Priority ค่าว่างหมายถึงไม่ได้กำหนด
+type Priority string
+
+const (
+	PriorityNone   Priority = ""
+	PriorityLow    Priority = "1"
+	PriorityMedium Priority = "2"
+	PriorityHigh   Priority = "3"
+)
+
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	}
+	return false
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	value, err := UnmarshalEnum(data, "priority", func(v string) bool { return Priority(v).IsValid() })
+	if err != nil {
+		return err
+	}
+	*p = Priority(value)
+	return nil
+}
+
+// AccountStatus สถานะบัญชีผู้ใช้ (User.Active)
+type AccountStatus string
+
+const (
+	AccountInactive AccountStatus = "0"
+	AccountActive   AccountStatus = "1"
+	AccountDeleted  AccountStatus = "2"
+)
+
+func (s AccountStatus) IsValid() bool {
+	switch s {
+	case AccountInactive, AccountActive, AccountDeleted:
+		return true
+	}
+	return false
+}
+
+func (s *AccountStatus) UnmarshalJSON(data []byte) error {
+	value, err := UnmarshalEnum(data, "active", func(v string) bool { return AccountStatus(v).IsValid() })
+	if err != nil {
+		return err
+	}
+	*s = AccountStatus(value)
+	return nil
+}
+
+// VerifyStatus สถานะการยืนยันอีเมล (User.Verify)
+type VerifyStatus string
+
+const (
+	VerifyPending VerifyStatus = "0"
+	Verified      VerifyStatus = "1"
+)
+
+func (s VerifyStatus) IsValid() bool {
+	return s == VerifyPending || s == Verified
+}
+
+func (s *VerifyStatus) UnmarshalJSON(data []byte) error {
+	value, err := UnmarshalEnum(data, "verify", func(v string) bool { return VerifyStatus(v).IsValid() })
+	if err != nil {
+		return err
+	}
+	*s = VerifyStatus(value)
+	return nil
+}
+
+type BoardType string
+
+const (
+	BoardPrivate BoardType = "private"
+	BoardGroup   BoardType = "group"
+)
+
+func (t BoardType) IsValid() bool {
+	return t == BoardPrivate || t == BoardGroup
+}
+
+func (t *BoardType) UnmarshalJSON(data []byte) error {
+	value, err := UnmarshalEnum(data, "board type", func(v string) bool { return BoardType(v).IsValid() })
+	if err != nil {
+		return err
+	}
+	*t = BoardType(value)
+	return nil
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type Label struct {
+	LabelID   string    `firestore:"labelid,omitempty"`
+	BoardID   string    `firestore:"boardid,omitempty"`
+	Name      string    `firestore:"name,omitempty"`
+	Color     string    `firestore:"color,omitempty"` // "#RRGGBB"
+	CreatedBy string    `firestore:"createdby,omitempty"`
+	CreatedAt time.Time `firestore:"createdat,omitempty"`
+	UpdatedAt time.Time `firestore:"updatedat,omitempty"`
+}
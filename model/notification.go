@@ -12,5 +12,6 @@ type Notification struct {
 	RecurringPattern *string    `firestore:"pattern,omitempty"`
 	Snooze           *time.Time `firestore:"snooze,omitempty"`
 	Send             string     `firestore:"send,omitempty"`
+	LastSentAt       *time.Time `firestore:"lastsentat,omitempty"` // ส่งครั้งล่าสุด ใช้หารอบถัดไปของ reminder ที่ทำซ้ำ
 	Updatedat        time.Time  `firestore:"updatedat,omitempty"`
 }
@@ -5,12 +5,20 @@ import (
 )
 
 type Tasks struct {
-	TaskID      string    `firestore:"taskid,omitempty"`
-	BoardID     string    `firestore:"boardid,omitempty"`
-	TaskName    string    `firestore:"taskname,omitempty"`
-	Description string    `firestore:"description,omitempty"`
-	Status      string    `firestore:"status,omitempty"`   // "0" = pending, "1" = in progress, "2" = completed
-	Priority    string    `firestore:"priority,omitempty"` // "1" = low, "2" = medium, "3" = high
-	CreatedBy   string    `firestore:"createdby,omitempty"`
-	UpdatedAt   time.Time `firestore:"updatedat,omitempty"`
+	TaskID      string     `firestore:"taskid,omitempty"`
+	BoardID     string     `firestore:"boardid,omitempty"`
+	TaskName    string     `firestore:"taskname,omitempty"`
+	Description string     `firestore:"description,omitempty"`
+	Status      TaskStatus `firestore:"status,omitempty"`   // columnid ของบอร์ด ค่าเริ่มต้น "0" = pending, "1" = in progress, "2" = completed
+	Priority    Priority   `firestore:"priority,omitempty"` // "1" = low, "2" = medium, "3" = high
+	CreatedBy   string     `firestore:"createdby,omitempty"`
+	Labels      []string   `firestore:"labels,omitempty"`    // labelid ของบอร์ดเดียวกัน
+	Assignees   []string   `firestore:"assignees,omitempty"` // userid ของสมาชิกบอร์ดที่รับผิดชอบ
+	StartDate   *time.Time `firestore:"startdate,omitempty"`
+	DueDate     *time.Time `firestore:"duedate,omitempty"`
+	AllDay      bool       `firestore:"allday"`                // true = startdate/duedate เป็นวันที่ล้วน เก็บเป็นเที่ยงคืน UTC
+	Position    float64    `firestore:"position"`              // ลำดับภายในคอลัมน์ (น้อยอยู่บน)
+	CompletedAt *time.Time `firestore:"completedat,omitempty"` // set automatically when the task enters a done column
+	CreatedAt   time.Time  `firestore:"createdat,omitempty"`
+	UpdatedAt   time.Time  `firestore:"updatedat,omitempty"`
 }
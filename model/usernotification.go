@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// UserNotification การแจ้งเตือนในแอปของผู้ใช้ (ต่างจาก Notification ที่เป็น reminder ของ task)
+type UserNotification struct {
+	NotificationID string    `firestore:"notificationid,omitempty"`
+	UserID         string    `firestore:"userid,omitempty"`  // ผู้รับการแจ้งเตือน
+	Type           string    `firestore:"type,omitempty"`    // "mention", "assigned", "unassigned", "reminder"
+	ActorID        string    `firestore:"actorid,omitempty"` // ผู้ที่ทำให้เกิดการแจ้งเตือน
+	BoardID        string    `firestore:"boardid,omitempty"`
+	TaskID         string    `firestore:"taskid,omitempty"`
+	CommentID      string    `firestore:"commentid,omitempty"`
+	Message        string    `firestore:"message,omitempty"`
+	Read           string    `firestore:"read,omitempty"` // "0" = unread, "1" = read
+	CreatedAt      time.Time `firestore:"createdat,omitempty"`
+}
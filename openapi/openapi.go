@@ -0,0 +1,297 @@
+// Package openapi สร้างเอกสาร OpenAPI 3 ของ API เวอร์ชัน /v1 จากรายการ route และชนิดใน dto
+// schema ของ request/response สร้างจาก struct จริงด้วย reflect จึงตรงกับสิ่งที่ handler ใช้เสมอ
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"myapp/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version เวอร์ชันของ API ที่เอกสารนี้อธิบาย
+const Version = "1.0.0"
+
+// Route อธิบาย endpoint หนึ่งตัว Path ใช้รูปแบบของ gin เช่น /v1/tasks/:taskid
+type Route struct {
+	Method  string
+	Path    string
+	Tag     string
+	Summary string
+	// Auth วิธียืนยันตัวตน ค่าว่างคือใช้ access token
+	Auth  Auth
+	Query []Param
+	// Body ค่าตัวอย่างของชนิด request body เช่น dto.SigninRequest{} หรือ Upload
+	Body interface{}
+	// Status สถานะเมื่อสำเร็จ ค่าเริ่มต้นคือ 200
+	Status int
+	// Response ค่าตัวอย่างของชนิด response เช่น dto.TaskResponse{} หรือ Fields สำหรับ gin.H
+	Response interface{}
+	// ContentType ของ response ที่ไม่ใช่ JSON เช่น text/calendar
+	ContentType string
+}
+
+type Auth int
+
+const (
+	AccessToken Auth = iota
+	RefreshToken
+	Public
+)
+
+// Param query parameter Type เป็นชนิดของ JSON schema เช่น string, integer, boolean
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Fields object ที่ handler ตอบด้วย gin.H ค่าของแต่ละ key เป็นค่าตัวอย่างของชนิด field นั้น
+type Fields map[string]interface{}
+
+// Upload request แบบ multipart/form-data ที่มีไฟล์อยู่ใน field ชื่อ Field
+type Upload struct {
+	Field string
+}
+
+type document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       info                            `json:"info"`
+	Servers    []server                        `json:"servers"`
+	Tags       []tag                           `json:"tags"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components components                      `json:"components"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type server struct {
+	URL string `json:"url"`
+}
+
+type tag struct {
+	Name string `json:"name"`
+}
+
+type operation struct {
+	Tags        []string              `json:"tags"`
+	Summary     string                `json:"summary"`
+	Security    []map[string][]string `json:"security"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat"`
+	Description  string `json:"description"`
+}
+
+const errorRef = "#/components/schemas/ErrorResponse"
+
+// Build สร้างเอกสารจากรายการ route
+func Build(routes []Route) ([]byte, error) {
+	g := &generator{components: map[string]*schema{}}
+	g.schemaOf(reflect.TypeOf(dto.ErrorResponse{}))
+
+	doc := document{
+		OpenAPI: "3.0.3",
+		Info: info{
+			Title:   "myapp API",
+			Version: Version,
+			Description: "ทุก endpoint อยู่ใต้ /v1 path เดิมที่ไม่มีเวอร์ชันยังใช้ได้ชั่วคราว " +
+				"แต่จะตอบพร้อม header Deprecation และ Link ที่ชี้ไปยัง path ใหม่",
+		},
+		Servers: []server{{URL: "/"}},
+		Paths:   map[string]map[string]operation{},
+		Components: components{
+			Schemas: g.components,
+			SecuritySchemes: map[string]securityScheme{
+				"accessToken":  {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "access token จาก /v1/auth/signin"},
+				"refreshToken": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "refresh token ใช้กับ /v1/auth/token/refresh เท่านั้น"},
+			},
+		},
+	}
+
+	seenTags := map[string]bool{}
+	for _, route := range routes {
+		path, params := pathParams(route.Path)
+		op := operation{
+			Tags:       []string{route.Tag},
+			Summary:    route.Summary,
+			Security:   security(route.Auth),
+			Parameters: params,
+			Responses: map[string]response{
+				"default": {Description: "error", Content: jsonContent(&schema{Ref: errorRef})},
+			},
+		}
+		for _, q := range route.Query {
+			op.Parameters = append(op.Parameters, parameter{
+				Name: q.Name, In: "query", Description: q.Description, Schema: &schema{Type: q.Type},
+			})
+		}
+		if route.Body != nil {
+			op.RequestBody = g.requestBody(route.Body)
+		}
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		op.Responses[fmt.Sprint(status)] = g.response(status, route)
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]operation{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			doc.Tags = append(doc.Tags, tag{Name: route.Tag})
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+var (
+	specOnce sync.Once
+	spec     []byte
+	specErr  error
+)
+
+// JSON เอกสารของ Routes สร้างครั้งเดียวแล้วใช้ซ้ำ
+func JSON() ([]byte, error) {
+	specOnce.Do(func() {
+		spec, specErr = Build(Routes)
+	})
+	return spec, specErr
+}
+
+// Undocumented route ใต้ /v1 ที่ลงทะเบียนไว้แต่ไม่มีในเอกสาร และ route ในเอกสารที่ไม่มีอยู่จริง
+// ใช้ตอนเริ่ม server เพื่อเตือนเมื่อเพิ่ม endpoint แล้วลืมอัปเดตเอกสาร
+func Undocumented(registered gin.RoutesInfo) []string {
+	documented := make(map[string]bool, len(Routes))
+	for _, route := range Routes {
+		documented[route.Method+" "+route.Path] = true
+	}
+
+	var missing []string
+	for _, route := range registered {
+		if !strings.HasPrefix(route.Path, "/v1/") {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		if documented[key] {
+			delete(documented, key)
+			continue
+		}
+		missing = append(missing, key)
+	}
+	for key := range documented {
+		missing = append(missing, key+" (not registered)")
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func (g *generator) requestBody(body interface{}) *requestBody {
+	if upload, ok := body.(Upload); ok {
+		return &requestBody{
+			Required: true,
+			Content: map[string]mediaType{
+				"multipart/form-data": {Schema: &schema{
+					Type:       "object",
+					Properties: map[string]*schema{upload.Field: {Type: "string", Format: "binary"}},
+					Required:   []string{upload.Field},
+				}},
+			},
+		}
+	}
+	return &requestBody{Required: true, Content: jsonContent(g.bodySchema(body))}
+}
+
+func (g *generator) response(status int, route Route) response {
+	resp := response{Description: http.StatusText(status)}
+	switch {
+	case route.ContentType != "":
+		resp.Content = map[string]mediaType{route.ContentType: {Schema: &schema{Type: "string", Format: "binary"}}}
+	case route.Response != nil:
+		resp.Content = jsonContent(g.bodySchema(route.Response))
+	}
+	return resp
+}
+
+func (g *generator) bodySchema(value interface{}) *schema {
+	if fields, ok := value.(Fields); ok {
+		return g.fieldsSchema(fields)
+	}
+	return g.schemaOf(reflect.TypeOf(value))
+}
+
+func jsonContent(s *schema) map[string]mediaType {
+	return map[string]mediaType{"application/json": {Schema: s}}
+}
+
+func security(auth Auth) []map[string][]string {
+	switch auth {
+	case Public:
+		return []map[string][]string{}
+	case RefreshToken:
+		return []map[string][]string{{"refreshToken": {}}}
+	}
+	return []map[string][]string{{"accessToken": {}}}
+}
+
+// pathParams แปลง /tasks/:taskid เป็น /tasks/{taskid} พร้อม path parameter
+func pathParams(path string) (string, []parameter) {
+	segments := strings.Split(path, "/")
+	var params []parameter
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, parameter{Name: name, In: "path", Required: true, Schema: &schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"net/http"
+	"time"
+
+	"myapp/dto"
+)
+
+var labelFilter = Param{Name: "label", Type: "string", Description: "labelid ที่ต้องการกรอง ส่งซ้ำได้หรือคั่นด้วย comma"}
+
+var tokens = Fields{"accessToken": "", "refreshToken": ""}
+
+// pageParams query ของรายการที่แบ่งหน้าด้วย pagination
+var pageParams = []Param{
+	{Name: "limit", Type: "integer", Description: "จำนวนต่อหน้า"},
+	{Name: "pageToken", Type: "string", Description: "nextPageToken จากหน้าก่อน ค่าว่างคือหน้าแรก"},
+}
+
+// Routes endpoint ทั้งหมดใต้ /v1 เมื่อเพิ่มหรือแก้ route ใน controller ต้องแก้ที่นี่ด้วย
+// server จะเตือนใน log ตอนเริ่มถ้ารายการนี้ไม่ตรงกับ route ที่ลงทะเบียนจริง
+var Routes = []Route{
+	// auth
+	{Method: http.MethodPost, Path: "/v1/auth/signin", Tag: "auth", Summary: "เข้าสู่ระบบด้วยอีเมลและรหัสผ่าน", Auth: Public,
+		Body: dto.SigninRequest{}, Response: Fields{"message": "", "token": tokens}},
+	{Method: http.MethodPost, Path: "/v1/auth/google", Tag: "auth", Summary: "เข้าสู่ระบบด้วย Google ID token", Auth: Public,
+		Body: dto.GoogleSignInRequest{}, Response: Fields{
+			"success": true, "message": "", "status": "",
+			"user":  Fields{"id": "", "email": "", "name": "", "role": ""},
+			"token": Fields{"accessToken": "", "refreshToken": "", "expiresIn": int64(0)},
+		}},
+	{Method: http.MethodPost, Path: "/v1/auth/signup", Tag: "auth", Summary: "สมัครสมาชิก", Auth: Public,
+		Body: dto.SignupRequest{}, Status: http.StatusCreated, Response: Fields{"message": "", "docID": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/lookup", Tag: "auth", Summary: "ค้นหาบัญชีจากอีเมล", Auth: Public,
+		Body: dto.EmailRequest{}, Response: Fields{"Email": "", "UserID": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/captcha", Tag: "auth", Summary: "ตรวจสอบ reCAPTCHA", Auth: Public,
+		Body: dto.CaptchaRequest{}, Response: Fields{"success": true, "score": float32(0), "action": "", "reasons": []string{}, "message": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/otp/identity", Tag: "auth", Summary: "ส่ง OTP ยืนยันตัวตน", Auth: Public,
+		Body: dto.IdentityOTPRequest{}, Response: Fields{"message": "", "ref": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/otp/password-reset", Tag: "auth", Summary: "ส่ง OTP สำหรับตั้งรหัสผ่านใหม่", Auth: Public,
+		Body: dto.IdentityOTPRequest{}, Response: Fields{"message": "", "ref": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/otp/send", Tag: "auth", Summary: "ส่ง OTP ทางอีเมล", Auth: Public,
+		Body: dto.SendemailRequest{}, Response: Fields{"message": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/otp/resend", Tag: "auth", Summary: "ส่ง OTP ใหม่", Auth: Public,
+		Body: dto.ResendOTPRequest{}, Response: Fields{"message": "", "ref": ""}},
+	{Method: http.MethodPut, Path: "/v1/auth/otp/verify", Tag: "auth", Summary: "ยืนยัน OTP", Auth: Public,
+		Body: dto.VerifyRequest{}, Response: Fields{"message": "", "accessToken": "", "refreshToken": ""}},
+	{Method: http.MethodPost, Path: "/v1/auth/token/refresh", Tag: "auth", Summary: "ขอ access token ใหม่", Auth: RefreshToken,
+		Response: Fields{"accessToken": ""}},
+	{Method: http.MethodPut, Path: "/v1/auth/password", Tag: "auth", Summary: "ตั้งรหัสผ่านใหม่หลังยืนยัน OTP", Auth: Public,
+		Body: dto.ResetPasswordRequest{}, Response: Fields{"message": ""}},
+
+	// users
+	{Method: http.MethodPost, Path: "/v1/users/search", Tag: "users", Summary: "ค้นหาผู้ใช้จากชื่อหรืออีเมล",
+		Body: dto.SearchUserRequest{}, Response: dto.Page[dto.PublicUserResponse]{}},
+	{Method: http.MethodPut, Path: "/v1/users/me", Tag: "users", Summary: "แก้ไขโปรไฟล์",
+		Body: dto.UpdateProfileRequest{}, Response: Fields{"message": "", "userid": ""}},
+	{Method: http.MethodDelete, Path: "/v1/users/me", Tag: "users", Summary: "ปิดหรือลบบัญชี",
+		Response: Fields{"message": ""}},
+
+	// boards
+	{Method: http.MethodGet, Path: "/v1/boards", Tag: "boards", Summary: "บอร์ดที่ผู้ใช้สร้างหรือเป็นสมาชิก",
+		Query: pageParams, Response: dto.Page[dto.BoardResponse]{}},
+	{Method: http.MethodPost, Path: "/v1/boards", Tag: "boards", Summary: "สร้างบอร์ด",
+		Body: dto.CreateBoardRequest{}, Status: http.StatusCreated, Response: Fields{"boardId": "", "message": "", "deep_link": ""}},
+	{Method: http.MethodDelete, Path: "/v1/boards/:boardid", Tag: "boards", Summary: "ลบบอร์ดพร้อม task ทั้งหมด",
+		Response: Fields{"message": "", "boardID": ""}},
+	{Method: http.MethodGet, Path: "/v1/boards/:boardid/tasks", Tag: "boards", Summary: "task ในบอร์ดเรียงตาม position",
+		Query:    append([]Param{{Name: "status", Type: "string", Description: "columnid ที่ต้องการ"}, labelFilter}, pageParams...),
+		Response: dto.Page[dto.TaskSummary]{}},
+	{Method: http.MethodPost, Path: "/v1/boards/:boardid/import", Tag: "boards", Summary: "นำเข้า task จากไฟล์ CSV หรือ JSON",
+		Query: []Param{
+			{Name: "dryrun", Type: "boolean", Description: "ตรวจสอบอย่างเดียวโดยไม่บันทึก ตอบ 200"},
+			{Name: "format", Type: "string", Description: "csv หรือ json ค่าเริ่มต้นดูจากนามสกุลไฟล์"},
+		},
+		Body: Upload{Field: "file"}, Status: http.StatusCreated, Response: dto.ImportResponse{}},
+	{Method: http.MethodGet, Path: "/v1/boards/:boardid/columns", Tag: "boards", Summary: "คอลัมน์ของบอร์ด",
+		Response: Fields{"columns": []dto.BoardColumn{}}},
+	{Method: http.MethodPut, Path: "/v1/boards/:boardid/columns", Tag: "boards", Summary: "แก้ไขคอลัมน์ของบอร์ด",
+		Body: dto.UpdateColumnsRequest{}, Response: Fields{"message": "", "columns": []dto.BoardColumn{}}},
+	{Method: http.MethodGet, Path: "/v1/boards/:boardid/labels", Tag: "boards", Summary: "label ของบอร์ด",
+		Response: Fields{"labels": []dto.LabelResponse{}}},
+	{Method: http.MethodPost, Path: "/v1/boards/:boardid/labels", Tag: "boards", Summary: "สร้าง label",
+		Body: dto.LabelRequest{}, Status: http.StatusCreated, Response: Fields{"message": "", "label": dto.LabelResponse{}}},
+	{Method: http.MethodPut, Path: "/v1/boards/:boardid/labels/:labelid", Tag: "boards", Summary: "แก้ไข label",
+		Body: dto.LabelRequest{}, Response: Fields{"message": "", "label": dto.LabelResponse{}}},
+	{Method: http.MethodDelete, Path: "/v1/boards/:boardid/labels/:labelid", Tag: "boards", Summary: "ลบ label",
+		Response: Fields{"message": "", "labelid": ""}},
+
+	// tasks
+	{Method: http.MethodPost, Path: "/v1/tasks", Tag: "tasks", Summary: "สร้าง task",
+		Body: dto.CreateTaskRequest{}, Status: http.StatusCreated, Response: Fields{"message": "", "taskID": ""}},
+	{Method: http.MethodGet, Path: "/v1/tasks/assigned", Tag: "tasks", Summary: "task ที่ได้รับมอบหมาย",
+		Query: append([]Param{{Name: "includecompleted", Type: "boolean", Description: "รวม task ที่เสร็จแล้ว"}, labelFilter},
+			pageParams...),
+		Response: dto.Page[dto.TaskSummary]{}},
+	{Method: http.MethodPost, Path: "/v1/tasks/batch", Tag: "tasks", Summary: "แก้ไขหลาย task ใน transaction เดียว",
+		Body: dto.BatchTaskRequest{}, Response: dto.BatchTaskResponse{}},
+	{Method: http.MethodGet, Path: "/v1/tasks/:taskid", Tag: "tasks", Summary: "รายละเอียด task",
+		Response: dto.TaskResponse{}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid", Tag: "tasks", Summary: "แก้ไข task",
+		Body: dto.UpdateTaskRequest{}, Response: Fields{"message": "", "taskID": ""}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid/move", Tag: "tasks", Summary: "ย้าย task ไปคอลัมน์หรือตำแหน่งใหม่",
+		Body: dto.MoveTaskRequest{}, Response: Fields{"message": "", "taskID": "", "columnid": "", "position": float64(0)}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid/assignees", Tag: "tasks", Summary: "กำหนดผู้รับผิดชอบ",
+		Body: dto.SetAssigneesRequest{}, Response: Fields{"message": "", "taskID": "", "assignees": []string{}}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid/labels", Tag: "tasks", Summary: "กำหนด label ของ task",
+		Body: dto.SetTaskLabelsRequest{}, Response: Fields{"message": "", "taskID": "", "labels": []string{}}},
+	{Method: http.MethodPost, Path: "/v1/tasks/:taskid/checklist", Tag: "tasks", Summary: "เพิ่ม checklist",
+		Body: dto.CreateChecklistRequest{}, Status: http.StatusCreated,
+		Response: Fields{"message": "", "checklist": dto.ChecklistResponse{}, "progress": 0}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid/checklist/reorder", Tag: "tasks", Summary: "เรียงลำดับ checklist ใหม่",
+		Body: dto.ReorderChecklistRequest{}, Response: Fields{"message": ""}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid/checklist/:checklistid/toggle", Tag: "tasks", Summary: "สลับสถานะ checklist",
+		Response: Fields{"message": "", "checklistid": "", "status": "", "progress": 0}},
+	{Method: http.MethodDelete, Path: "/v1/tasks/:taskid/checklist/:checklistid", Tag: "tasks", Summary: "ลบ checklist",
+		Response: Fields{"message": "", "progress": 0}},
+	{Method: http.MethodGet, Path: "/v1/tasks/:taskid/comments", Tag: "tasks", Summary: "ความคิดเห็นของ task",
+		Query: pageParams, Response: dto.Page[dto.CommentResponse]{}},
+	{Method: http.MethodPost, Path: "/v1/tasks/:taskid/comments", Tag: "tasks", Summary: "เพิ่มความคิดเห็น",
+		Body: dto.CommentRequest{}, Status: http.StatusCreated, Response: Fields{"message": "", "comment": dto.CommentResponse{}}},
+	{Method: http.MethodPut, Path: "/v1/tasks/:taskid/comments/:commentid", Tag: "tasks", Summary: "แก้ไขความคิดเห็น",
+		Body: dto.CommentRequest{}, Response: Fields{"message": "", "comment": dto.CommentResponse{}}},
+	{Method: http.MethodDelete, Path: "/v1/tasks/:taskid/comments/:commentid", Tag: "tasks", Summary: "ลบความคิดเห็น",
+		Response: Fields{"message": "", "commentid": ""}},
+	{Method: http.MethodGet, Path: "/v1/tasks/:taskid/attachments", Tag: "tasks", Summary: "ไฟล์แนบของ task",
+		Response: Fields{"attachments": []dto.AttachmentResponse{}}},
+	{Method: http.MethodPost, Path: "/v1/tasks/:taskid/attachments", Tag: "tasks", Summary: "อัปโหลดไฟล์แนบ",
+		Body: Upload{Field: "file"}, Status: http.StatusCreated, Response: Fields{"message": "", "attachment": dto.AttachmentResponse{}}},
+	{Method: http.MethodGet, Path: "/v1/tasks/:taskid/attachments/:attachmentid", Tag: "tasks", Summary: "ดาวน์โหลดไฟล์แนบ",
+		ContentType: "application/octet-stream"},
+	{Method: http.MethodDelete, Path: "/v1/tasks/:taskid/attachments/:attachmentid", Tag: "tasks", Summary: "ลบไฟล์แนบ",
+		Response: Fields{"message": "", "attachmentid": ""}},
+
+	// agenda และ search
+	{Method: http.MethodGet, Path: "/v1/agenda", Tag: "agenda", Summary: "task ที่ครบกำหนดแยกตามวัน",
+		Query: []Param{
+			{Name: "from", Type: "string", Description: "วันแรก YYYY-MM-DD ค่าเริ่มต้นคือวันนี้"},
+			{Name: "to", Type: "string", Description: "วันสุดท้าย YYYY-MM-DD ค่าเริ่มต้นคือ from + 6 วัน"},
+			labelFilter,
+		},
+		Response: dto.AgendaResponse{}},
+	{Method: http.MethodGet, Path: "/v1/my-day", Tag: "agenda", Summary: "task ที่ค้างและครบกำหนดวันนี้",
+		Query: []Param{labelFilter}, Response: dto.AgendaResponse{}},
+	{Method: http.MethodGet, Path: "/v1/search", Tag: "search", Summary: "ค้นหา task และบอร์ด",
+		Query: append([]Param{
+			{Name: "q", Type: "string", Description: "คำค้น"},
+			{Name: "type", Type: "string", Description: "task หรือ board ค่าว่างคือทั้งหมด"},
+		}, pageParams...),
+		Response: dto.Page[dto.SearchItem]{}},
+
+	// notifications
+	{Method: http.MethodGet, Path: "/v1/notifications", Tag: "notifications", Summary: "การแจ้งเตือนในแอปล่าสุดก่อน",
+		Query:    append([]Param{{Name: "unread", Type: "boolean", Description: "เฉพาะที่ยังไม่ได้อ่าน"}}, pageParams...),
+		Response: dto.Page[dto.UserNotificationResponse]{}},
+
+	// calendar
+	{Method: http.MethodGet, Path: "/v1/calendar", Tag: "calendar", Summary: "URL ของ calendar feed",
+		Response: Fields{"url": "", "boardUrl": "", "updatedAt": time.Time{}, "instructions": ""}},
+	{Method: http.MethodPost, Path: "/v1/calendar/rotate", Tag: "calendar", Summary: "เปลี่ยน secret ของ calendar feed",
+		Response: Fields{"url": "", "boardUrl": "", "updatedAt": time.Time{}, "instructions": "", "message": ""}},
+	{Method: http.MethodGet, Path: "/v1/calendar/feeds/:secret", Tag: "calendar", Summary: "iCalendar feed ของผู้ใช้ (secret ลงท้ายด้วย .ics)", Auth: Public,
+		ContentType: "text/calendar"},
+	{Method: http.MethodGet, Path: "/v1/calendar/feeds/:secret/boards/:boardid", Tag: "calendar", Summary: "iCalendar feed ของบอร์ด (boardid ลงท้ายด้วย .ics)", Auth: Public,
+		ContentType: "text/calendar"},
+}
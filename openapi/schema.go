@@ -0,0 +1,147 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"myapp/dto"
+	"myapp/model"
+)
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	OneOf                []*schema          `json:"oneOf,omitempty"`
+}
+
+// overrides ชนิดที่ reflect อย่างเดียวบอกรูปแบบไม่ครบ เช่น enum ที่รับได้ทั้ง string และตัวเลข
+var overrides = map[reflect.Type]schema{
+	reflect.TypeOf(time.Time{}): {Type: "string", Format: "date-time"},
+	reflect.TypeOf(model.TaskStatus("")): {
+		Type:        "string",
+		Description: "columnid ของบอร์ด (\"0\", \"1\", \"2\" คือคอลัมน์เริ่มต้น)",
+	},
+	reflect.TypeOf(model.Priority("")): {
+		Type:        "string",
+		Enum:        []string{"", "1", "2", "3"},
+		Description: "ค่าว่างคือไม่ได้กำหนด, 1 = low, 2 = medium, 3 = high (รับตัวเลขได้ด้วย)",
+	},
+	reflect.TypeOf(model.AccountStatus("")): {Type: "string", Enum: []string{"0", "1", "2"}, Description: "0 = inactive, 1 = active, 2 = deleted"},
+	reflect.TypeOf(model.VerifyStatus("")):  {Type: "string", Enum: []string{"0", "1"}, Description: "0 = รอยืนยัน, 1 = ยืนยันแล้ว"},
+	reflect.TypeOf(model.BoardType("")):     {Type: "string", Enum: []string{"private", "group"}},
+	reflect.TypeOf(dto.GroupFlag("")): {
+		OneOf:       []*schema{{Type: "string", Enum: []string{"0", "1"}}, {Type: "boolean"}},
+		Description: "0 หรือ false = บอร์ดส่วนตัว, 1 หรือ true = บอร์ดกลุ่ม",
+	},
+}
+
+// generator แปลงชนิดของ Go เป็น schema โดยอ่าน json tag และ binding:"required"
+// struct ที่มีชื่อจะถูกเก็บเป็น component แล้วอ้างถึงด้วย $ref
+type generator struct {
+	components map[string]*schema
+}
+
+func (g *generator) schemaOf(t reflect.Type) *schema {
+	if s, ok := overrides[t]; ok {
+		return &s
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schemaOf(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := g.components[name]; !ok {
+			// ใส่ไว้ก่อนเพื่อไม่ให้ struct ที่อ้างถึงตัวเองวนไม่จบ
+			g.components[name] = &schema{}
+			*g.components[name] = *g.structSchema(t)
+		}
+		return &schema{Ref: "#/components/schemas/" + name}
+	}
+	// interface{} รับค่าได้ทุกชนิด
+	return &schema{}
+}
+
+func (g *generator) structSchema(t reflect.Type) *schema {
+	s := &schema{Type: "object", Properties: map[string]*schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schemaOf(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+	return s
+}
+
+// componentName ชื่อ component ของ struct ชนิด generic เช่น Page[myapp/dto.TaskSummary] เป็น PageTaskSummary
+func componentName(t reflect.Type) string {
+	name, params, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return name
+	}
+	for _, param := range strings.Split(strings.TrimSuffix(params, "]"), ",") {
+		name += param[strings.LastIndex(param, ".")+1:]
+	}
+	return name
+}
+
+// fieldsSchema object ที่ handler ตอบด้วย gin.H
+func (g *generator) fieldsSchema(fields Fields) *schema {
+	s := &schema{Type: "object", Properties: make(map[string]*schema, len(fields))}
+	for name, example := range fields {
+		if nested, ok := example.(Fields); ok {
+			s.Properties[name] = g.fieldsSchema(nested)
+			continue
+		}
+		s.Properties[name] = g.schemaOf(reflect.TypeOf(example))
+	}
+	return s
+}
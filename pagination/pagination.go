@@ -0,0 +1,177 @@
+// Package pagination แบ่งหน้ารายการจาก Firestore ด้วย cursor (StartAfter) แทนการดึงทั้งหมดหรือใช้ offset
+// page token คือค่าของ field ที่ใช้ OrderBy ของรายการสุดท้ายในหน้าก่อน encode ให้ client ส่งกลับมาตามเดิมโดยไม่ต้องตีความ
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"myapp/apperror"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidToken = apperror.New(apperror.CodeInvalidPageToken)
+
+// Request หน้าที่ client ขอ scope ระบุว่า token ใช้กับรายการไหน เช่น "comments:<taskid>"
+// token จากรายการอื่นจึงใช้ไม่ได้ แม้จะแก้ token เองได้ query ก็ยังกรองด้วยสิทธิ์ของผู้ใช้อยู่ดี
+type Request struct {
+	Limit  int
+	scope  string
+	cursor []interface{}
+}
+
+// New ตรวจ limit (0 คือ defaultLimit) และถอด token ของหน้าก่อน token ว่างคือหน้าแรก
+func New(scope, token string, limit, defaultLimit, maxLimit int) (Request, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 1 || limit > maxLimit {
+		return Request{}, apperror.Invalid(fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+	}
+	req := Request{Limit: limit, scope: scope}
+	if token != "" {
+		cursor, err := decode(scope, token)
+		if err != nil {
+			return Request{}, ErrInvalidToken.Wrap(err)
+		}
+		req.cursor = cursor
+	}
+	return req, nil
+}
+
+// FromQuery อ่าน ?limit= และ ?pageToken=
+func FromQuery(c *gin.Context, scope string, defaultLimit, maxLimit int) (Request, error) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return Request{}, apperror.Invalid(fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+		}
+		limit = n
+	}
+	return New(scope, c.Query("pageToken"), limit, defaultLimit, maxLimit)
+}
+
+// Query ต่อ StartAfter กับ Limit ให้ q ซึ่งต้อง OrderBy ครบทุก field ที่อยู่ใน cursor แล้ว
+// ดึงเกินมาหนึ่งรายการเพื่อดูว่ายังมีหน้าถัดไปหรือไม่ ผลลัพธ์ต้องส่งต่อให้ Next
+func (r Request) Query(q firestore.Query) firestore.Query {
+	if len(r.cursor) > 0 {
+		q = q.StartAfter(r.cursor...)
+	}
+	return q.Limit(r.Limit + 1)
+}
+
+// After ค่า cursor ของหน้าก่อน (nil คือหน้าแรก) ใช้เมื่อต้องรวมผลจากหลาย query เอง
+func (r Request) After() []interface{} {
+	return r.cursor
+}
+
+// StartAfter คืน Request เดิมที่เลื่อน cursor ไปหลัง cursor ที่ระบุ
+// ใช้เมื่อต้องอ่านหลายรอบเพื่อเติมหน้าเดียวให้เต็มหลังกรองผลในหน่วยความจำ
+func (r Request) StartAfter(cursor ...interface{}) Request {
+	r.cursor = cursor
+	return r
+}
+
+// Token สร้าง token ของหน้าที่เริ่มหลัง cursor ใช้เมื่อหน้าปัจจุบันจบก่อนรายการสุดท้ายที่อ่าน
+func (r Request) Token(cursor []interface{}) (string, error) {
+	return encode(r.scope, cursor)
+}
+
+// Next ตัด items ให้เหลือ Limit รายการ และสร้าง token ของหน้าถัดไปจากรายการสุดท้ายที่คืน
+// cursor คืนค่าของ field ตามลำดับเดียวกับ OrderBy รองรับ string, time.Time, int, int64 และ float64
+// ชนิดอื่นคืน error ซึ่งเป็นความผิดพลาดของโค้ดผู้เรียก ให้ตอบเป็น apperror.Internal
+func Next[T any](r Request, items []T, cursor func(T) []interface{}) ([]T, string, error) {
+	if len(items) <= r.Limit {
+		return items, "", nil
+	}
+	items = items[:r.Limit]
+	token, err := encode(r.scope, cursor(items[len(items)-1]))
+	if err != nil {
+		return nil, "", err
+	}
+	return items, token, nil
+}
+
+type token struct {
+	Scope  string  `json:"s"`
+	Values []value `json:"v"`
+}
+
+// value เก็บชนิดไว้ด้วย เพราะ StartAfter ต้องได้ค่าชนิดเดียวกับ field เช่น timestamp ไม่ใช่ string
+type value struct {
+	String *string    `json:"s,omitempty"`
+	Time   *time.Time `json:"t,omitempty"`
+	Int    *int64     `json:"i,omitempty"`
+	Float  *float64   `json:"f,omitempty"`
+}
+
+func encode(scope string, cursor []interface{}) (string, error) {
+	t := token{Scope: scope, Values: make([]value, len(cursor))}
+	for i, v := range cursor {
+		switch v := v.(type) {
+		case string:
+			t.Values[i].String = &v
+		case time.Time:
+			t.Values[i].Time = &v
+		case int:
+			n := int64(v)
+			t.Values[i].Int = &n
+		case int64:
+			t.Values[i].Int = &v
+		case float64:
+			t.Values[i].Float = &v
+		default:
+			return "", fmt.Errorf("pagination: unsupported cursor value %T", v)
+		}
+	}
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decode(scope, encoded string) ([]interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var t token
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, err
+	}
+	if t.Scope != scope {
+		return nil, fmt.Errorf("token scope %q does not match %q", t.Scope, scope)
+	}
+	if len(t.Values) == 0 {
+		return nil, fmt.Errorf("token has no cursor")
+	}
+
+	cursor := make([]interface{}, len(t.Values))
+	for i, v := range t.Values {
+		switch {
+		case v.String != nil:
+			cursor[i] = *v.String
+		case v.Time != nil:
+			cursor[i] = *v.Time
+		case v.Int != nil:
+			cursor[i] = *v.Int
+		case v.Float != nil:
+			cursor[i] = *v.Float
+		default:
+			return nil, fmt.Errorf("token value %d is empty", i)
+		}
+	}
+	return cursor, nil
+}
@@ -0,0 +1,206 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	valid := mustEncode(t, "comments:t1", []interface{}{"c1"})
+	empty := mustEncode(t, "comments:t1", nil)
+
+	tests := []struct {
+		name      string
+		scope     string
+		token     string
+		limit     int
+		wantLimit int
+		wantErr   bool
+		wantToken bool
+	}{
+		{name: "default limit", scope: "comments:t1", wantLimit: DefaultLimit},
+		{name: "explicit limit", scope: "comments:t1", limit: 5, wantLimit: 5},
+		{name: "limit too large", scope: "comments:t1", limit: MaxLimit + 1, wantErr: true},
+		{name: "negative limit", scope: "comments:t1", limit: -1, wantErr: true},
+		{name: "valid token", scope: "comments:t1", token: valid, wantLimit: DefaultLimit},
+		{name: "token from another scope", scope: "comments:t2", token: valid, wantErr: true, wantToken: true},
+		{name: "not base64", scope: "comments:t1", token: "***", wantErr: true, wantToken: true},
+		{name: "not json", scope: "comments:t1", token: "bm90IGpzb24", wantErr: true, wantToken: true},
+		{name: "empty cursor", scope: "comments:t1", token: empty, wantErr: true, wantToken: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := New(tt.scope, tt.token, tt.limit, DefaultLimit, MaxLimit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantToken && !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("error = %v, want ErrInvalidToken", err)
+			}
+			if err == nil && req.Limit != tt.wantLimit {
+				t.Fatalf("limit = %d, want %d", req.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	at := time.Date(2024, 3, 12, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor []interface{}
+		want   []interface{}
+	}{
+		{name: "string", cursor: []interface{}{"abc"}, want: []interface{}{"abc"}},
+		{name: "time and id", cursor: []interface{}{at, "t1"}, want: []interface{}{at, "t1"}},
+		{name: "int is widened to int64", cursor: []interface{}{3}, want: []interface{}{int64(3)}},
+		{name: "int64 and float64", cursor: []interface{}{int64(7), 1.5}, want: []interface{}{int64(7), 1.5}},
+		{name: "empty string is kept", cursor: []interface{}{""}, want: []interface{}{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decode("scope", mustEncode(t, "scope", tt.cursor))
+			if err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if gt, ok := got[i].(time.Time); ok {
+					if !gt.Equal(tt.want[i].(time.Time)) {
+						t.Fatalf("value %d = %v, want %v", i, gt, tt.want[i])
+					}
+					continue
+				}
+				if got[i] != tt.want[i] {
+					t.Fatalf("value %d = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	cursor := func(s string) []interface{} { return []interface{}{s} }
+
+	tests := []struct {
+		name       string
+		limit      int
+		items      []string
+		want       []string
+		wantCursor []interface{}
+	}{
+		{name: "empty", limit: 2, items: nil, want: nil},
+		{name: "fewer than limit", limit: 2, items: []string{"a"}, want: []string{"a"}},
+		{name: "exactly limit", limit: 2, items: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "one extra item means another page", limit: 2, items: []string{"a", "b", "c"},
+			want: []string{"a", "b"}, wantCursor: []interface{}{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := New("list", "", tt.limit, DefaultLimit, MaxLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, next, err := Next(req, tt.items, cursor)
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("items = %v, want %v", got, tt.want)
+			}
+			if tt.wantCursor == nil {
+				if next != "" {
+					t.Fatalf("next = %q, want empty", next)
+				}
+				return
+			}
+			following, err := New("list", next, tt.limit, DefaultLimit, MaxLimit)
+			if err != nil {
+				t.Fatalf("next token rejected: %v", err)
+			}
+			if !reflect.DeepEqual(following.After(), tt.wantCursor) {
+				t.Fatalf("cursor = %v, want %v", following.After(), tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestStartAfterAndToken(t *testing.T) {
+	at := time.Date(2024, 3, 12, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor []interface{}
+	}{
+		{name: "single value", cursor: []interface{}{"t1"}},
+		{name: "time and id", cursor: []interface{}{at, "t1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := New("assigned:u1", "", 5, DefaultLimit, MaxLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			moved := req.StartAfter(tt.cursor...)
+			if moved.Limit != req.Limit || !reflect.DeepEqual(moved.After(), tt.cursor) || req.After() != nil {
+				t.Fatalf("StartAfter() = %+v, original %+v", moved, req)
+			}
+
+			token, err := req.Token(tt.cursor)
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			following, err := New("assigned:u1", token, 5, DefaultLimit, MaxLimit)
+			if err != nil {
+				t.Fatalf("token rejected: %v", err)
+			}
+			if len(following.After()) != len(tt.cursor) {
+				t.Fatalf("cursor = %v, want %v", following.After(), tt.cursor)
+			}
+		})
+	}
+}
+
+func TestNextUnsupportedCursor(t *testing.T) {
+	at := time.Date(2024, 3, 12, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor []interface{}
+	}{
+		{name: "time pointer", cursor: []interface{}{&at}},
+		{name: "unsigned integer", cursor: []interface{}{uint(3)}},
+		{name: "bool after a valid value", cursor: []interface{}{"t1", true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := New("list", "", 1, DefaultLimit, MaxLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			items, next, err := Next(req, []string{"a", "b"}, func(string) []interface{} { return tt.cursor })
+			if err == nil || items != nil || next != "" {
+				t.Fatalf("Next() = %v, %q, %v, want an error", items, next, err)
+			}
+		})
+	}
+}
+
+func mustEncode(t *testing.T, scope string, cursor []interface{}) string {
+	t.Helper()
+	token, err := encode(scope, cursor)
+	if err != nil {
+		t.Fatalf("encode error = %v", err)
+	}
+	return token
+}
@@ -0,0 +1,91 @@
+// Package ratelimit จำกัดอัตราคำขอด้วย token bucket: แต่ละ key มีถังจุได้ Burst token
+// และเติมคืน PerMinute token ต่อนาที คำขอหนึ่งครั้งใช้หนึ่ง token
+// MemoryStore นับแยกในแต่ละ instance ถ้ารันหลาย instance ให้ทำ Store ที่เก็บถังไว้ในที่ร่วมกัน เช่น Redis
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+func (l Limit) rate() float64 {
+	return float64(l.PerMinute) / time.Minute.Seconds()
+}
+
+// Result ผลของการขอใช้ token RetryAfter มีค่าเมื่อ Allowed เป็นเท็จเท่านั้น
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store ที่เก็บถังของแต่ละ key Take ต้องอ่านและหัก token ในขั้นตอนเดียว (atomic)
+// เพื่อไม่ให้คำขอพร้อมกันหลายครั้งผ่านไปได้เกิน Burst
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full เวลาที่ถังจะเต็มอีกครั้ง หลังจากนั้นลบทิ้งได้โดยไม่เปลี่ยนผลลัพธ์
+	full time.Time
+}
+
+// MemoryStore เก็บถังไว้ใน map ของ process
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := s.now()
+	rate := limit.rate()
+	capacity := float64(limit.Burst)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// ล้างถังที่เต็มแล้วเป็นระยะ เพื่อไม่ให้ map โตไม่สิ้นสุด
+	if now.After(s.nextSweep) {
+		for k, b := range s.buckets {
+			if !now.Before(b.full) {
+				delete(s.buckets, k)
+			}
+		}
+		s.nextSweep = now.Add(sweepInterval)
+	}
+
+	b := s.buckets[key]
+	if b == nil {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	result := Result{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+	b.full = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	return result, nil
+}
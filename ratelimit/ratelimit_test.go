@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock เวลาที่เลื่อนเองได้ เพื่อทดสอบการเติม token โดยไม่ต้องรอจริง
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	return s, clock
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	// 60 ต่อนาที = เติม 1 token ต่อวินาที
+	limit := Limit{PerMinute: 60, Burst: 3}
+
+	type step struct {
+		advance    time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst then deny",
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "partial refill shortens retry after",
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{advance: 250 * time.Millisecond, allowed: false, remaining: 0, retryAfter: 750 * time.Millisecond},
+				{advance: 750 * time.Millisecond, allowed: true, remaining: 0},
+			},
+		},
+		{
+			name: "refill is capped at burst",
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{advance: time.Hour, allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "denied request does not consume a token",
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+				{advance: time.Second, allowed: true, remaining: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, clock := newTestStore()
+			for i, st := range tt.steps {
+				clock.t = clock.t.Add(st.advance)
+				got, err := s.Take(context.Background(), "k", limit)
+				if err != nil {
+					t.Fatalf("step %d: unexpected error: %v", i, err)
+				}
+				if got.Allowed != st.allowed || got.Remaining != st.remaining {
+					t.Fatalf("step %d: got allowed=%v remaining=%d, want allowed=%v remaining=%d",
+						i, got.Allowed, got.Remaining, st.allowed, st.remaining)
+				}
+				if diff := got.RetryAfter - st.retryAfter; diff < -time.Millisecond || diff > time.Millisecond {
+					t.Fatalf("step %d: retry after = %v, want %v", i, got.RetryAfter, st.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{PerMinute: 1, Burst: 1}
+
+	if r, _ := s.Take(context.Background(), "a", limit); !r.Allowed {
+		t.Fatal("first request for a should be allowed")
+	}
+	if r, _ := s.Take(context.Background(), "a", limit); r.Allowed {
+		t.Fatal("second request for a should be denied")
+	}
+	if r, _ := s.Take(context.Background(), "b", limit); !r.Allowed {
+		t.Fatal("first request for b should be allowed")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	limit := Limit{PerMinute: 60, Burst: 2}
+
+	tests := []struct {
+		name string
+		// advance เวลาที่ผ่านไปหลังใช้ token ของ key "old" หมด ก่อนมีคำขอของ key อื่น
+		advance time.Duration
+		kept    bool
+	}{
+		{name: "refilled bucket is removed", advance: sweepInterval + time.Second, kept: false},
+		{name: "bucket still refilling is kept", advance: time.Second, kept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, clock := newTestStore()
+			// คำขอแรกตั้งรอบ sweep ถัดไป
+			s.Take(context.Background(), "warmup", limit)
+			clock.t = clock.t.Add(sweepInterval + time.Millisecond)
+			s.Take(context.Background(), "old", limit)
+			s.Take(context.Background(), "old", limit)
+
+			clock.t = clock.t.Add(tt.advance)
+			if tt.kept {
+				// บังคับให้ถึงรอบ sweep โดยไม่ให้ถัง old เต็ม
+				s.nextSweep = clock.t.Add(-time.Nanosecond)
+			}
+			s.Take(context.Background(), "new", limit)
+
+			if _, ok := s.buckets["old"]; ok != tt.kept {
+				t.Fatalf("bucket kept = %v, want %v", ok, tt.kept)
+			}
+			if _, ok := s.buckets["warmup"]; ok {
+				t.Fatal("warmup bucket should have been swept")
+			}
+		})
+	}
+}
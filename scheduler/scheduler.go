@@ -0,0 +1,119 @@
+// Package scheduler รันงานเบื้องหลังเป็นรอบ เช่น การส่ง reminder ของ task
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"myapp/metrics"
+	"myapp/tracing"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Job ควรหยุดทำงานเมื่อ ctx ถูกยกเลิก
+type Job func(ctx context.Context) error
+
+type entry struct {
+	name     string
+	interval time.Duration
+	run      Job
+	// heartbeat เวลา (UnixNano) ที่รอบล่าสุดจบ หรือเวลาที่เริ่ม scheduler ถ้ายังไม่เคยรัน
+	heartbeat *atomic.Int64
+}
+
+// stallFactor งานที่ไม่ได้จบรอบนานเกินกี่เท่าของ interval ถือว่าค้าง
+const stallFactor = 3
+
+type Scheduler struct {
+	jobs []entry
+	wg   sync.WaitGroup
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	// running อ่านจาก readiness probe ซึ่งรันคนละ goroutine กับ Start/Stop
+	running atomic.Bool
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every ลงทะเบียนงานที่รันทุก interval ต้องเรียกก่อน Start
+func (s *Scheduler) Every(name string, interval time.Duration, run Job) {
+	s.jobs = append(s.jobs, entry{name: name, interval: interval, run: run, heartbeat: new(atomic.Int64)})
+}
+
+// Start รันแต่ละงานใน goroutine ของตัวเอง รอบถัดไปเริ่มหลังรอบก่อนเสร็จ จึงไม่รันซ้อนกัน
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx, s.cancel = context.WithCancel(ctx)
+	now := time.Now().UnixNano()
+	for _, job := range s.jobs {
+		job.heartbeat.Store(now)
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	s.running.Store(true)
+}
+
+func (s *Scheduler) loop(ctx context.Context, job entry) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			runCtx, span := tracing.Start(ctx, "job "+job.name)
+			err := job.run(runCtx)
+			tracing.End(span, err)
+			metrics.ObserveJob(job.name, err, time.Since(start))
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "scheduled job failed", "job", job.name, "error", err)
+			}
+			job.heartbeat.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// Check ใช้กับ readiness probe คืน error ถ้ายังไม่เริ่ม หรือมีงานที่ไม่ได้จบรอบนานเกินไป
+func (s *Scheduler) Check(ctx context.Context) error {
+	if !s.running.Load() {
+		return fmt.Errorf("scheduler is not running")
+	}
+	now := time.Now()
+	for _, job := range s.jobs {
+		last := time.Unix(0, job.heartbeat.Load())
+		if now.Sub(last) > stallFactor*job.interval {
+			return fmt.Errorf("%s has not completed a run since %s", job.name, last.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// Stop ยกเลิกงานทั้งหมดแล้วรอให้รอบที่กำลังรันอยู่จบ หรือจนกว่า ctx จะหมดเวลา
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.running.Store(false)
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
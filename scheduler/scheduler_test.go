@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   bool
+		stop    bool
+		stalled bool
+		wantErr bool
+	}{
+		{name: "not started", wantErr: true},
+		{name: "running", start: true},
+		{name: "stalled job", start: true, stalled: true, wantErr: true},
+		{name: "stopped", start: true, stop: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			s.Every("job", time.Hour, func(context.Context) error { return nil })
+			if tt.start {
+				s.Start(context.Background())
+				defer s.Stop(context.Background())
+			}
+			if tt.stalled {
+				s.jobs[0].heartbeat.Store(time.Now().Add(-stallFactor*time.Hour - time.Minute).UnixNano())
+			}
+			if tt.stop {
+				if err := s.Stop(context.Background()); err != nil {
+					t.Fatalf("Stop() error = %v", err)
+				}
+			}
+			if err := s.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCheckConcurrentWithStart readiness probe อาจถูกเรียกพร้อมกับ Start และ Stop (ตรวจด้วย go test -race)
+func TestCheckConcurrentWithStart(t *testing.T) {
+	s := New()
+	s.Every("job", time.Hour, func(context.Context) error { return nil })
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = s.Check(context.Background())
+		}
+	}()
+	s.Start(context.Background())
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,150 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+)
+
+const (
+	collectionName = "SearchIndex"
+	// Firestore จำกัดจำนวนค่าใน query แบบ "in" ไว้ที่ 30 ค่า
+	firestoreInLimit = 30
+	// maxCandidates จำนวนเอกสารสูงสุดที่ดึงมาจัดอันดับต่อกลุ่มบอร์ด
+	// เอกสารที่ตรงคำค้นเกินจากนี้จะไม่ถูกจัดอันดับและไม่อยู่ในผลลัพธ์หน้าใดเลย
+	maxCandidates = 500
+	// maxStoredTokens ป้องกันเอกสารใหญ่เกินไปเมื่อคำอธิบายยาวมาก
+	maxStoredTokens = 300
+)
+
+// FirestoreIndex เก็บดัชนีใน collection SearchIndex (id = "<kind>_<id>")
+// ค้นด้วย array-contains ของคำที่เลือกได้แคบที่สุดแล้วจัดอันดับในหน่วยความจำ
+// ผลลัพธ์มาจากผู้สมัครไม่เกิน maxCandidates ต่อ 30 บอร์ด คำค้นกว้างมากจึงอาจไม่ครบทุกเอกสาร
+// ต้องมี composite index: tokens (array-contains) + boardid และ tokens + kind + boardid
+type FirestoreIndex struct {
+	client *firestore.Client
+}
+
+func NewFirestoreIndex(client *firestore.Client) *FirestoreIndex {
+	return &FirestoreIndex{client: client}
+}
+
+type indexedDocument struct {
+	Kind      Kind      `firestore:"kind"`
+	ID        string    `firestore:"id"`
+	BoardID   string    `firestore:"boardid"`
+	Title     string    `firestore:"title"`
+	Body      string    `firestore:"body,omitempty"`
+	Tokens    []string  `firestore:"tokens"`
+	UpdatedAt time.Time `firestore:"updatedat"`
+}
+
+func (f *FirestoreIndex) Put(ctx context.Context, docs ...Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	collection := f.client.Collection(collectionName)
+	return f.bulk(ctx, func(bw *firestore.BulkWriter) ([]*firestore.BulkWriterJob, error) {
+		jobs := make([]*firestore.BulkWriterJob, 0, len(docs))
+		for _, doc := range docs {
+			// คำจากชื่อมาก่อน จึงไม่ถูกตัดทิ้งเมื่อเกิน maxStoredTokens
+			tokens := uniqueTokens(append(Tokenize(doc.Title), Tokenize(doc.Body)...))
+			if len(tokens) > maxStoredTokens {
+				tokens = tokens[:maxStoredTokens]
+			}
+			job, err := bw.Set(collection.Doc(documentKey(doc.Kind, doc.ID)), indexedDocument{
+				Kind:      doc.Kind,
+				ID:        doc.ID,
+				BoardID:   doc.BoardID,
+				Title:     doc.Title,
+				Body:      doc.Body,
+				Tokens:    tokens,
+				UpdatedAt: doc.UpdatedAt,
+			})
+			if err != nil {
+				return jobs, err
+			}
+			jobs = append(jobs, job)
+		}
+		return jobs, nil
+	})
+}
+
+func (f *FirestoreIndex) Delete(ctx context.Context, kind Kind, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	collection := f.client.Collection(collectionName)
+	return f.bulk(ctx, func(bw *firestore.BulkWriter) ([]*firestore.BulkWriterJob, error) {
+		jobs := make([]*firestore.BulkWriterJob, 0, len(ids))
+		for _, id := range ids {
+			job, err := bw.Delete(collection.Doc(documentKey(kind, id)))
+			if err != nil {
+				return jobs, err
+			}
+			jobs = append(jobs, job)
+		}
+		return jobs, nil
+	})
+}
+
+// bulk รอให้ทุกงานเสร็จแล้วรวม error ทั้งหมด
+func (f *FirestoreIndex) bulk(ctx context.Context, enqueue func(*firestore.BulkWriter) ([]*firestore.BulkWriterJob, error)) error {
+	bw := f.client.BulkWriter(ctx)
+	jobs, err := enqueue(bw)
+	bw.End()
+
+	errs := []error{err}
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *FirestoreIndex) Search(ctx context.Context, q Query) (Result, error) {
+	terms := uniqueTokens(Tokenize(q.Text))
+	if len(terms) == 0 {
+		return Result{}, ErrEmptyQuery
+	}
+
+	// คำที่ยาวที่สุดมักพบในเอกสารน้อยที่สุด
+	selective := terms[0]
+	for _, term := range terms[1:] {
+		if utf8.RuneCountInString(term) > utf8.RuneCountInString(selective) {
+			selective = term
+		}
+	}
+
+	var candidates []Document
+	for start := 0; start < len(q.BoardIDs); start += firestoreInLimit {
+		end := min(start+firestoreInLimit, len(q.BoardIDs))
+		query := f.client.Collection(collectionName).Where("tokens", "array-contains", selective)
+		if len(q.Kinds) == 1 {
+			query = query.Where("kind", "==", q.Kinds[0])
+		}
+		docs, err := query.Where("boardid", "in", q.BoardIDs[start:end]).Limit(maxCandidates).Documents(ctx).GetAll()
+		if err != nil {
+			return Result{}, err
+		}
+		for _, snap := range docs {
+			var doc indexedDocument
+			if err := snap.DataTo(&doc); err != nil {
+				return Result{}, err
+			}
+			candidates = append(candidates, Document{
+				Kind:      doc.Kind,
+				ID:        doc.ID,
+				BoardID:   doc.BoardID,
+				Title:     doc.Title,
+				Body:      doc.Body,
+				UpdatedAt: doc.UpdatedAt,
+			})
+		}
+	}
+	return Rank(candidates, q)
+}
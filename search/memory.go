@@ -0,0 +1,48 @@
+package search
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryIndex เก็บดัชนีไว้ในหน่วยความจำ ใช้สำหรับทดสอบ
+type MemoryIndex struct {
+	mu   sync.RWMutex
+	docs map[string]Document
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{docs: make(map[string]Document)}
+}
+
+func (m *MemoryIndex) Put(ctx context.Context, docs ...Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, doc := range docs {
+		m.docs[documentKey(doc.Kind, doc.ID)] = doc
+	}
+	return nil
+}
+
+func (m *MemoryIndex) Delete(ctx context.Context, kind Kind, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.docs, documentKey(kind, id))
+	}
+	return nil
+}
+
+func (m *MemoryIndex) Search(ctx context.Context, q Query) (Result, error) {
+	m.mu.RLock()
+	docs := make([]Document, 0, len(m.docs))
+	for _, doc := range m.docs {
+		docs = append(docs, doc)
+	}
+	m.mu.RUnlock()
+	return Rank(docs, q)
+}
+
+func documentKey(kind Kind, id string) string {
+	return string(kind) + "_" + id
+}
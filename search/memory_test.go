@@ -0,0 +1,78 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryIndex(t *testing.T) {
+	ctx := context.Background()
+	query := Query{Text: "budget", BoardIDs: []string{"b1"}}
+
+	tests := []struct {
+		name    string
+		apply   func(idx *MemoryIndex) error
+		wantIDs []string
+	}{
+		{
+			name: "put makes documents searchable",
+			apply: func(idx *MemoryIndex) error {
+				return idx.Put(ctx,
+					Document{Kind: KindTask, ID: "t1", BoardID: "b1", Title: "Budget"},
+					Document{Kind: KindTask, ID: "t2", BoardID: "b1", Title: "Plan", Body: "budget"})
+			},
+			wantIDs: []string{"t1", "t2"},
+		},
+		{
+			name: "put replaces a document with the same kind and id",
+			apply: func(idx *MemoryIndex) error {
+				if err := idx.Put(ctx, Document{Kind: KindTask, ID: "t1", BoardID: "b1", Title: "Budget"}); err != nil {
+					return err
+				}
+				return idx.Put(ctx, Document{Kind: KindTask, ID: "t1", BoardID: "b1", Title: "Renamed"})
+			},
+		},
+		{
+			name: "same id with a different kind is a separate document",
+			apply: func(idx *MemoryIndex) error {
+				return idx.Put(ctx,
+					Document{Kind: KindTask, ID: "x", BoardID: "b1", Title: "Budget"},
+					Document{Kind: KindBoard, ID: "x", BoardID: "b1", Title: "Budget"})
+			},
+			wantIDs: []string{"x", "x"},
+		},
+		{
+			name: "delete removes only the given kind",
+			apply: func(idx *MemoryIndex) error {
+				if err := idx.Put(ctx,
+					Document{Kind: KindTask, ID: "x", BoardID: "b1", Title: "Budget"},
+					Document{Kind: KindBoard, ID: "x", BoardID: "b1", Title: "Budget"}); err != nil {
+					return err
+				}
+				return idx.Delete(ctx, KindTask, "x", "missing")
+			},
+			wantIDs: []string{"x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := NewMemoryIndex()
+			if err := tt.apply(idx); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			result, err := idx.Search(ctx, query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			var ids []string
+			for _, hit := range result.Hits {
+				ids = append(ids, hit.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("ids = %q, want %q", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	titleWeight = 3
+	bodyWeight  = 1
+	// maxTermFrequency ไม่ให้คำที่ซ้ำมากๆ ในคำอธิบายดันคะแนนเกินไป
+	maxTermFrequency = 3
+	// phraseBonus ชื่อที่มีคำค้นทั้งวลีจะอยู่เหนือชื่อที่มีแค่บางส่วน
+	phraseBonus = 2
+)
+
+// Rank กรองเอกสารที่มีทุกคำในคำค้นและอยู่ในบอร์ดที่อนุญาต
+// เรียงตามคะแนน แล้วตามเวลาแก้ไขล่าสุด จากนั้นแบ่งหน้าตาม Limit และ Offset
+func Rank(docs []Document, q Query) (Result, error) {
+	terms := uniqueTokens(Tokenize(q.Text))
+	if len(terms) == 0 {
+		return Result{}, ErrEmptyQuery
+	}
+	phrase := strings.ToLower(strings.TrimSpace(q.Text))
+
+	boards := make(map[string]bool, len(q.BoardIDs))
+	for _, id := range q.BoardIDs {
+		boards[id] = true
+	}
+
+	var hits []Hit
+	for _, doc := range docs {
+		if !boards[doc.BoardID] || !hasKind(q.Kinds, doc.Kind) {
+			continue
+		}
+		if score, ok := scoreDocument(doc, terms, phrase); ok {
+			hits = append(hits, Hit{Document: doc, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if !hits[i].UpdatedAt.Equal(hits[j].UpdatedAt) {
+			return hits[i].UpdatedAt.After(hits[j].UpdatedAt)
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if q.Offset >= len(hits) {
+		return Result{}, nil
+	}
+	hits = hits[q.Offset:]
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return Result{Hits: hits}, nil
+}
+
+func scoreDocument(doc Document, terms []string, phrase string) (float64, bool) {
+	title := countTokens(doc.Title)
+	body := countTokens(doc.Body)
+
+	score := 0
+	for _, term := range terms {
+		inTitle, inBody := title[term], body[term]
+		if inTitle == 0 && inBody == 0 {
+			return 0, false
+		}
+		score += titleWeight*min(inTitle, maxTermFrequency) + bodyWeight*min(inBody, maxTermFrequency)
+	}
+	if strings.Contains(strings.ToLower(doc.Title), phrase) {
+		score += phraseBonus * len(terms)
+	}
+	return float64(score), true
+}
+
+func countTokens(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, token := range Tokenize(text) {
+		counts[token]++
+	}
+	return counts
+}
+
+func hasKind(kinds []Kind, kind Kind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRank(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	docs := []Document{
+		{Kind: KindTask, ID: "t1", BoardID: "b1", Title: "Write report", Body: "quarterly numbers", UpdatedAt: base},
+		{Kind: KindTask, ID: "t2", BoardID: "b1", Title: "Review", Body: "write report draft", UpdatedAt: base.Add(time.Hour)},
+		{Kind: KindTask, ID: "t3", BoardID: "b2", Title: "Write report", Body: "", UpdatedAt: base},
+		{Kind: KindBoard, ID: "b1", BoardID: "b1", Title: "Reports", UpdatedAt: base},
+		{Kind: KindTask, ID: "t4", BoardID: "b1", Title: "Report", Body: "report report report report", UpdatedAt: base},
+		{Kind: KindTask, ID: "t5", BoardID: "b1", Title: "ประชุมทีม", UpdatedAt: base},
+		{Kind: KindTask, ID: "t6", BoardID: "b1", Title: "Report write", UpdatedAt: base.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		wantIDs []string
+		wantErr error
+	}{
+		{
+			name:    "all terms are required and title matches rank first",
+			query:   Query{Text: "write report", BoardIDs: []string{"b1"}},
+			wantIDs: []string{"t1", "t6", "t2"},
+		},
+		{
+			name:    "only boards the user can access",
+			query:   Query{Text: "write report", BoardIDs: []string{"b2"}},
+			wantIDs: []string{"t3"},
+		},
+		{
+			name:    "term frequency is capped",
+			query:   Query{Text: "report", BoardIDs: []string{"b1"}, Kinds: []Kind{KindTask}},
+			wantIDs: []string{"t4", "t6", "t1", "t2"},
+		},
+		{
+			name:    "kind filter",
+			query:   Query{Text: "reports", BoardIDs: []string{"b1"}, Kinds: []Kind{KindBoard}},
+			wantIDs: []string{"b1"},
+		},
+		{
+			name:    "thai substring",
+			query:   Query{Text: "ประชุม", BoardIDs: []string{"b1"}},
+			wantIDs: []string{"t5"},
+		},
+		{
+			name:    "offset and limit",
+			query:   Query{Text: "write report", BoardIDs: []string{"b1"}, Offset: 1, Limit: 1},
+			wantIDs: []string{"t6"},
+		},
+		{
+			name:  "offset past the end",
+			query: Query{Text: "write report", BoardIDs: []string{"b1"}, Offset: 10},
+		},
+		{
+			name:    "empty query",
+			query:   Query{Text: " - ", BoardIDs: []string{"b1"}},
+			wantErr: ErrEmptyQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Rank(docs, tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			var ids []string
+			for _, hit := range result.Hits {
+				ids = append(ids, hit.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("ids = %q, want %q", ids, tt.wantIDs)
+			}
+		})
+	}
+}
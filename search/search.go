@@ -0,0 +1,57 @@
+// Package search ดัชนีค้นหา task และบอร์ดจากชื่อและคำอธิบาย
+// ใช้ FirestoreIndex บน production และ MemoryIndex สำหรับทดสอบ
+package search
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrEmptyQuery คำค้นไม่มีคำที่ใช้ค้นหาได้ (เช่น มีแต่เครื่องหมาย)
+var ErrEmptyQuery = errors.New("query has no searchable words")
+
+type Kind string
+
+const (
+	KindTask  Kind = "task"
+	KindBoard Kind = "board"
+)
+
+// Document ข้อมูลหนึ่งรายการในดัชนี ผลการค้นหาจะถูกจำกัดด้วย BoardID
+type Document struct {
+	Kind      Kind
+	ID        string
+	BoardID   string
+	Title     string
+	Body      string
+	UpdatedAt time.Time
+}
+
+// Query คำค้นพร้อมบอร์ดที่ผู้ใช้เข้าถึงได้ Kinds ว่างหมายถึงทุกประเภท
+type Query struct {
+	Text     string
+	BoardIDs []string
+	Kinds    []Kind
+	Limit    int
+	Offset   int
+}
+
+type Hit struct {
+	Document
+	Score float64
+}
+
+// Result ผลการค้นหาที่เรียงตามคะแนนแล้ว ไม่มีจำนวนทั้งหมดเพราะ FirestoreIndex จัดอันดับจากผู้สมัครที่จำกัดจำนวน
+// ผู้เรียกที่ต้องรู้ว่ามีหน้าถัดไปให้ขอ Limit เกินไปหนึ่งรายการ
+type Result struct {
+	Hits []Hit
+}
+
+type Index interface {
+	// Put เพิ่มหรือแทนที่ข้อมูลเดิมที่มี Kind และ ID เดียวกัน
+	Put(ctx context.Context, docs ...Document) error
+	// Delete ไม่คืน error ถ้าไม่มีข้อมูลอยู่แล้ว
+	Delete(ctx context.Context, kind Kind, ids ...string) error
+	Search(ctx context.Context, q Query) (Result, error)
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize แยกข้อความเป็นคำสำหรับค้นหา (ตัวพิมพ์เล็กทั้งหมด)
+// ภาษาไทยไม่เว้นวรรคระหว่างคำ จึงแบ่งเป็นคู่ตัวอักษรที่ติดกัน (bigram)
+// เช่น "ประชุม" -> "ปร", "ระ", "ะช", "ชุ", "ุม" ส่วนภาษาอื่นแยกตามช่องว่างและเครื่องหมาย
+func Tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	thai := false
+
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		if thai {
+			tokens = append(tokens, thaiBigrams(word)...)
+		} else {
+			tokens = append(tokens, string(word))
+		}
+		word = word[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isThaiLetter(r):
+			if !thai {
+				flush()
+				thai = true
+			}
+			word = append(word, r)
+		case unicode.Is(unicode.Thai, r):
+			// ไม้ยมก ไปยาลน้อย และเครื่องหมายอื่นของภาษาไทยใช้แบ่งคำ
+			flush()
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if thai {
+				flush()
+				thai = false
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+func isThaiLetter(r rune) bool {
+	return unicode.Is(unicode.Thai, r) && r != 'ๆ' && r != 'ฯ' &&
+		(unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r))
+}
+
+func thaiBigrams(word []rune) []string {
+	if len(word) == 1 {
+		return []string{string(word)}
+	}
+	bigrams := make([]string, 0, len(word)-1)
+	for i := 0; i+1 < len(word); i++ {
+		bigrams = append(bigrams, string(word[i:i+2]))
+	}
+	return bigrams
+}
+
+func uniqueTokens(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	unique := tokens[:0:0]
+	for _, token := range tokens {
+		if !seen[token] {
+			seen[token] = true
+			unique = append(unique, token)
+		}
+	}
+	return unique
+}
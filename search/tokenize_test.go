@@ -0,0 +1,38 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "lowercases and splits on punctuation", text: "Fix Login-Bug, ASAP!", want: []string{"fix", "login", "bug", "asap"}},
+		{name: "digits stay in the word", text: "release v2 2024", want: []string{"release", "v2", "2024"}},
+		{name: "thai is split into bigrams", text: "ประชุม", want: []string{"ปร", "ระ", "ะช", "ชุ", "ุม"}},
+		{name: "single thai letter", text: "ก", want: []string{"ก"}},
+		{name: "thai repetition mark splits words", text: "ดีๆ", want: []string{"ดี"}},
+		{name: "mixed scripts", text: "ส่งงานQA", want: []string{"ส่", "่ง", "งง", "งา", "าน", "qa"}},
+		{name: "only punctuation", text: "?!-", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueTokens(t *testing.T) {
+	got := uniqueTokens([]string{"a", "b", "a", "c", "b"})
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("uniqueTokens() = %q, want %q", got, want)
+	}
+}
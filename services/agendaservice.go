@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"myapp/model"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Firestore จำกัดจำนวนค่าใน query แบบ "in" ไว้ที่ 30 ค่า
+const firestoreInLimit = 30
+
+// GetAccessibleBoardIDs คืนค่า boardid ทั้งหมดที่ผู้ใช้เป็นเจ้าของหรือเป็นสมาชิก
+func GetAccessibleBoardIDs(ctx context.Context, firestoreClient *firestore.Client, userID string) ([]string, error) {
+	seen := make(map[string]bool)
+	var boardIDs []string
+
+	owned, err := firestoreClient.Collection("Boards").Where("createdby", "==", userID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range owned {
+		if !seen[doc.Ref.ID] {
+			seen[doc.Ref.ID] = true
+			boardIDs = append(boardIDs, doc.Ref.ID)
+		}
+	}
+
+	memberships, err := firestoreClient.Collection("BoardUser").Where("userid", "==", userID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range memberships {
+		var boardUser model.BoardUser
+		if err := doc.DataTo(&boardUser); err != nil {
+			return nil, err
+		}
+		if boardUser.BoardID != "" && !seen[boardUser.BoardID] {
+			seen[boardUser.BoardID] = true
+			boardIDs = append(boardIDs, boardUser.BoardID)
+		}
+	}
+
+	return boardIDs, nil
+}
+
+// chunkIDs แบ่ง ids เป็นกลุ่มละไม่เกิน firestoreInLimit
+func chunkIDs(ids []string) [][]string {
+	var chunks [][]string
+	for start := 0; start < len(ids); start += firestoreInLimit {
+		end := start + firestoreInLimit
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
+}
+
+// GetTasksByBoardIDs ดึง task ทั้งหมดของบอร์ดที่ระบุ
+func GetTasksByBoardIDs(ctx context.Context, firestoreClient *firestore.Client, boardIDs []string) ([]model.Tasks, error) {
+	var tasks []model.Tasks
+	for _, chunk := range chunkIDs(boardIDs) {
+		docs, err := firestoreClient.Collection("Tasks").Where("boardid", "in", chunk).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			var task model.Tasks
+			if err := doc.DataTo(&task); err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// GetNotificationsByTaskIDs ดึง reminder ของ task โดยคืนค่าเป็น map ตาม taskid
+func GetNotificationsByTaskIDs(ctx context.Context, firestoreClient *firestore.Client, taskIDs []string) (map[string]model.Notification, error) {
+	notifications := make(map[string]model.Notification)
+	for _, chunk := range chunkIDs(taskIDs) {
+		docs, err := firestoreClient.Collection("NotificationTasks").Where("taskid", "in", chunk).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			var notification model.Notification
+			if err := doc.DataTo(&notification); err != nil {
+				return nil, err
+			}
+			notifications[notification.TaskID] = notification
+		}
+	}
+	return notifications, nil
+}
+
+type AgendaEntry struct {
+	Task       model.Tasks
+	OccursAt   time.Time
+	Pattern    string
+	Occurrence bool // true เมื่อเป็นรอบที่ขยายมาจากการทำซ้ำ ไม่ใช่วันครบกำหนดจริง
+	Overdue    bool
+}
+
+type AgendaDay struct {
+	Date    time.Time
+	Entries []AgendaEntry
+}
+
+type Agenda struct {
+	Overdue []AgendaEntry
+	Days    []AgendaDay
+}
+
+// BuildAgenda จัดกลุ่ม task ตามวันในช่วง [from, to) ตาม timezone ของผู้ใช้
+// task ที่ทำซ้ำจะถูกขยายเป็นหลายรอบ ส่วน task ที่เลยกำหนดก่อน from และยังไม่เสร็จจะอยู่ใน Overdue
+func BuildAgenda(tasks []model.Tasks, notifications map[string]model.Notification, from, to, now time.Time, loc *time.Location) Agenda {
+	var agenda Agenda
+	dayIndex := make(map[string]int)
+	for day := StartOfDay(from, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		dayIndex[day.Format("2006-01-02")] = len(agenda.Days)
+		agenda.Days = append(agenda.Days, AgendaDay{Date: day})
+	}
+
+	for _, task := range tasks {
+		due := TaskDueAt(&task, loc)
+		if due == nil {
+			continue
+		}
+		completed := IsTaskCompleted(&task)
+
+		var pattern string
+		if notification, ok := notifications[task.TaskID]; ok {
+			pattern = NormalizePattern(notification.RecurringPattern)
+		}
+
+		if pattern == "" && !completed && due.Before(from) {
+			agenda.Overdue = append(agenda.Overdue, newAgendaEntry(task, *due, *due, pattern, true, loc))
+			continue
+		}
+
+		for _, at := range Occurrences(*due, pattern, from, to, loc) {
+			idx, ok := dayIndex[at.In(loc).Format("2006-01-02")]
+			if !ok {
+				continue
+			}
+			overdue := pattern == "" && !completed && isPastDue(task, at, now, loc)
+			agenda.Days[idx].Entries = append(agenda.Days[idx].Entries, newAgendaEntry(task, at, *due, pattern, overdue, loc))
+		}
+	}
+
+	sortEntries(agenda.Overdue)
+	for i := range agenda.Days {
+		sortEntries(agenda.Days[i].Entries)
+	}
+	return agenda
+}
+
+// newAgendaEntry at และ due เป็นเวลาจริงตาม loc
+// รอบของ task แบบทั้งวันเก็บเป็นวันที่ล้วนเหมือนวันครบกำหนดของ task
+func newAgendaEntry(task model.Tasks, at, due time.Time, pattern string, overdue bool, loc *time.Location) AgendaEntry {
+	entry := AgendaEntry{
+		Task:       task,
+		OccursAt:   at,
+		Pattern:    pattern,
+		Occurrence: !at.Equal(due),
+		Overdue:    overdue,
+	}
+	if task.AllDay {
+		entry.OccursAt = AllDayDate(at, loc)
+	}
+	return entry
+}
+
+// isPastDue task แบบทั้งวันจะถือว่าเลยกำหนดเมื่อพ้นวันนั้นไปแล้ว
+func isPastDue(task model.Tasks, at, now time.Time, loc *time.Location) bool {
+	if task.AllDay {
+		return !now.Before(StartOfDay(at, loc).AddDate(0, 0, 1))
+	}
+	return at.Before(now)
+}
+
+// sortEntries เรียง task แบบทั้งวันไว้ก่อน แล้วตามด้วยเวลา
+func sortEntries(entries []AgendaEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Task.AllDay != entries[j].Task.AllDay {
+			return entries[i].Task.AllDay
+		}
+		return entries[i].OccursAt.Before(entries[j].OccursAt)
+	})
+}
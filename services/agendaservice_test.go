@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"myapp/model"
+)
+
+func TestBuildAgenda(t *testing.T) {
+	bangkok := mustLoad(t, "Asia/Bangkok")
+	newYork := mustLoad(t, "America/New_York")
+	date := func(d int) *time.Time { return ptrTime(time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)) }
+	daily := PatternDaily
+
+	type entry struct {
+		taskID     string
+		occursAt   time.Time
+		occurrence bool
+		overdue    bool
+	}
+
+	tests := []struct {
+		name          string
+		loc           *time.Location
+		tasks         []model.Tasks
+		notifications map[string]model.Notification
+		wantOverdue   []entry
+		wantDays      map[string][]entry
+	}{
+		{
+			name: "overdue all-day task keeps its calendar date and sorts before timed tasks",
+			loc:  bangkok,
+			tasks: []model.Tasks{
+				{TaskID: "timed", DueDate: ptrTime(time.Date(2024, 3, 9, 1, 0, 0, 0, time.UTC))},
+				{TaskID: "allday", DueDate: date(10), AllDay: true},
+				{TaskID: "done", DueDate: date(8), AllDay: true, CompletedAt: date(8)},
+			},
+			wantOverdue: []entry{
+				{taskID: "allday", occursAt: *date(10), overdue: true},
+				{taskID: "timed", occursAt: time.Date(2024, 3, 9, 1, 0, 0, 0, time.UTC), overdue: true},
+			},
+		},
+		{
+			name:     "all-day task lands on its date east of UTC",
+			loc:      bangkok,
+			tasks:    []model.Tasks{{TaskID: "allday", DueDate: date(13), AllDay: true}},
+			wantDays: map[string][]entry{"2024-03-13": {{taskID: "allday", occursAt: *date(13)}}},
+		},
+		{
+			name:     "all-day task lands on its date west of UTC",
+			loc:      newYork,
+			tasks:    []model.Tasks{{TaskID: "allday", DueDate: date(13), AllDay: true}},
+			wantDays: map[string][]entry{"2024-03-13": {{taskID: "allday", occursAt: *date(13)}}},
+		},
+		{
+			name:          "recurring all-day task expands without becoming overdue",
+			loc:           bangkok,
+			tasks:         []model.Tasks{{TaskID: "daily", DueDate: date(1), AllDay: true}},
+			notifications: map[string]model.Notification{"daily": {RecurringPattern: &daily}},
+			wantDays: map[string][]entry{
+				"2024-03-12": {{taskID: "daily", occursAt: *date(12), occurrence: true}},
+				"2024-03-13": {{taskID: "daily", occursAt: *date(13), occurrence: true}},
+				"2024-03-14": {{taskID: "daily", occursAt: *date(14), occurrence: true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := time.Date(2024, 3, 12, 0, 0, 0, 0, tt.loc)
+			to := from.AddDate(0, 0, 3)
+			now := from.Add(time.Hour)
+			agenda := BuildAgenda(tt.tasks, tt.notifications, from, to, now, tt.loc)
+
+			check := func(where string, got []AgendaEntry, want []entry) {
+				t.Helper()
+				if len(got) != len(want) {
+					t.Fatalf("%s: got %d entries, want %d", where, len(got), len(want))
+				}
+				for i, w := range want {
+					g := got[i]
+					if g.Task.TaskID != w.taskID || !g.OccursAt.Equal(w.occursAt) || g.Occurrence != w.occurrence || g.Overdue != w.overdue {
+						t.Errorf("%s[%d] = %s at %v occurrence %v overdue %v, want %s at %v occurrence %v overdue %v", where, i,
+							g.Task.TaskID, g.OccursAt, g.Occurrence, g.Overdue, w.taskID, w.occursAt, w.occurrence, w.overdue)
+					}
+				}
+			}
+
+			check("overdue", agenda.Overdue, tt.wantOverdue)
+			for _, day := range agenda.Days {
+				key := day.Date.Format("2006-01-02")
+				check(key, day.Entries, tt.wantDays[key])
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"mime"
+	"myapp/apperror"
+	"myapp/blobstore"
+	"myapp/model"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+)
+
+const (
+	// MaxAttachmentSize ขนาดไฟล์แนบสูงสุด 10 MB
+	MaxAttachmentSize = 10 << 20
+	// MaxAttachmentsPerTask จำนวนไฟล์แนบสูงสุดต่อ task
+	MaxAttachmentsPerTask = 20
+)
+
+var ErrUnsupportedFileType = apperror.New(apperror.CodeFileUnsupportedType)
+
+// allowedAttachmentTypes ชนิดไฟล์ที่อนุญาต ตรวจจากเนื้อหาไฟล์ไม่ใช่จากนามสกุล
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+	"text/csv":        true,
+}
+
+// ไฟล์ Office แบบใหม่เป็น zip จึงต้องดูนามสกุลประกอบ
+var officeTypes = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+func AttachmentCollection(firestoreClient *firestore.Client, taskID string) *firestore.CollectionRef {
+	return firestoreClient.Collection("Tasks").Doc(taskID).Collection("Attachments")
+}
+
+func AttachmentKey(taskID, attachmentID string) string {
+	return "tasks/" + taskID + "/" + attachmentID
+}
+
+// TaskSubcollections subcollection ทั้งหมดของ task ที่ต้องลบตามเมื่อ task ถูกลบ
+func TaskSubcollections(firestoreClient *firestore.Client, taskID string) []*firestore.CollectionRef {
+	return []*firestore.CollectionRef{
+		ChecklistCollection(firestoreClient, taskID),
+		CommentCollection(firestoreClient, taskID),
+		AttachmentCollection(firestoreClient, taskID),
+	}
+}
+
+// DetectAttachmentType ตรวจชนิดไฟล์จาก 512 ไบต์แรกของไฟล์
+func DetectAttachmentType(head []byte, fileName string) (string, error) {
+	detected := http.DetectContentType(head)
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return "", ErrUnsupportedFileType
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	switch {
+	case mediaType == "application/zip" && officeTypes[ext] != "":
+		return officeTypes[ext], nil
+	case mediaType == "text/plain" && ext == ".csv":
+		return "text/csv", nil
+	case allowedAttachmentTypes[mediaType]:
+		return mediaType, nil
+	}
+	return "", ErrUnsupportedFileType
+}
+
+// GetAttachments ดึงรายการไฟล์แนบของ task เรียงตามเวลาที่อัปโหลด
+func GetAttachments(ctx context.Context, firestoreClient *firestore.Client, taskID string) ([]model.Attachment, error) {
+	docs, err := AttachmentCollection(firestoreClient, taskID).
+		OrderBy("createdat", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make([]model.Attachment, 0, len(docs))
+	for _, doc := range docs {
+		var attachment model.Attachment
+		if err := doc.DataTo(&attachment); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// DeleteBlobs ลบไฟล์หลังจากลบข้อมูลใน Firestore แล้ว
+// ถ้าลบไม่สำเร็จจะแค่ log ไว้ เพราะไฟล์ที่ค้างไม่มีเอกสารอ้างอิงแล้ว
+func DeleteBlobs(ctx context.Context, store blobstore.Store, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "failed to delete blob", "key", key, "error", err)
+		}
+	}
+}
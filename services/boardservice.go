@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/model"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrBoardNotFound = apperror.New(apperror.CodeBoardNotFound)
+	ErrTaskNotFound  = apperror.New(apperror.CodeTaskNotFound)
+	ErrAccessDenied  = apperror.New(apperror.CodeAccessDenied)
+)
+
+func GetBoard(ctx context.Context, firestoreClient *firestore.Client, boardID string) (*model.Board, error) {
+	docSnap, err := firestoreClient.Collection("Boards").Doc(boardID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrBoardNotFound
+		}
+		return nil, err
+	}
+
+	var board model.Board
+	if err := docSnap.DataTo(&board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// IsBoardMember ตรวจสอบว่าผู้ใช้เป็นเจ้าของหรือเป็นสมาชิกของบอร์ด
+func IsBoardMember(ctx context.Context, firestoreClient *firestore.Client, board *model.Board, userID string) (bool, error) {
+	if board.CreatedBy == userID {
+		return true, nil
+	}
+
+	docs, err := firestoreClient.Collection("BoardUser").
+		Where("boardid", "==", board.BoardID).
+		Where("userid", "==", userID).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return false, err
+	}
+	return len(docs) > 0, nil
+}
+
+// GetBoardForUser ดึงข้อมูลบอร์ดพร้อมตรวจสอบสิทธิ์การเข้าถึงของผู้ใช้
+func GetBoardForUser(ctx context.Context, firestoreClient *firestore.Client, boardID, userID string) (*model.Board, error) {
+	board, err := GetBoard(ctx, firestoreClient, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := IsBoardMember(ctx, firestoreClient, board, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrAccessDenied
+	}
+	return board, nil
+}
+
+// GetBoardMemberIDs คืนค่า userid ของเจ้าของบอร์ดและสมาชิกทั้งหมด
+func GetBoardMemberIDs(ctx context.Context, firestoreClient *firestore.Client, board *model.Board) ([]string, error) {
+	docs, err := firestoreClient.Collection("BoardUser").Where("boardid", "==", board.BoardID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return boardMemberIDs(board, docs)
+}
+
+// GetBoardMemberIDsInTx เหมือน GetBoardMemberIDs แต่อ่านบอร์ดและสมาชิกใน transaction
+// ถ้าสมาชิกถูกเพิ่มหรือนำออกก่อน commit transaction จะถูกรันใหม่ด้วยข้อมูลล่าสุด
+func GetBoardMemberIDsInTx(tx *firestore.Transaction, firestoreClient *firestore.Client, boardID string) ([]string, error) {
+	docSnap, err := tx.Get(firestoreClient.Collection("Boards").Doc(boardID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrBoardNotFound
+		}
+		return nil, err
+	}
+	var board model.Board
+	if err := docSnap.DataTo(&board); err != nil {
+		return nil, err
+	}
+	board.BoardID = boardID
+
+	docs, err := tx.Documents(firestoreClient.Collection("BoardUser").Where("boardid", "==", boardID)).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return boardMemberIDs(&board, docs)
+}
+
+func boardMemberIDs(board *model.Board, docs []*firestore.DocumentSnapshot) ([]string, error) {
+	memberIDs := []string{board.CreatedBy}
+	seen := map[string]bool{board.CreatedBy: true}
+	for _, doc := range docs {
+		var boardUser model.BoardUser
+		if err := doc.DataTo(&boardUser); err != nil {
+			return nil, err
+		}
+		if boardUser.UserID != "" && !seen[boardUser.UserID] {
+			seen[boardUser.UserID] = true
+			memberIDs = append(memberIDs, boardUser.UserID)
+		}
+	}
+	return memberIDs, nil
+}
+
+// GetBoardMembers ดึงข้อมูลผู้ใช้ของสมาชิกทุกคนในบอร์ด
+func GetBoardMembers(ctx context.Context, firestoreClient *firestore.Client, board *model.Board) ([]model.User, error) {
+	memberIDs, err := GetBoardMemberIDs(ctx, firestoreClient, board)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		refs = append(refs, firestoreClient.Collection("Users").Doc(id))
+	}
+	docs, err := firestoreClient.GetAll(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]model.User, 0, len(docs))
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return nil, err
+		}
+		members = append(members, user)
+	}
+	return members, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"myapp/apperror"
+	"myapp/model"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrFeedNotFound = apperror.New(apperror.CodeCalendarFeedNotFound)
+
+func generateFeedSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GetOrCreateFeed ดึง secret ของ calendar feed ของผู้ใช้ ถ้ายังไม่มีจะสร้างใหม่
+func GetOrCreateFeed(ctx context.Context, firestoreClient *firestore.Client, userID string) (*model.CalendarFeed, error) {
+	docRef := firestoreClient.Collection("CalendarFeeds").Doc(userID)
+	docSnap, err := docRef.Get(ctx)
+	if err == nil {
+		var feed model.CalendarFeed
+		if err := docSnap.DataTo(&feed); err != nil {
+			return nil, err
+		}
+		return &feed, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	return RotateFeed(ctx, firestoreClient, userID)
+}
+
+// RotateFeed สร้าง secret ใหม่ ทำให้ URL เดิมใช้ไม่ได้อีก
+func RotateFeed(ctx context.Context, firestoreClient *firestore.Client, userID string) (*model.CalendarFeed, error) {
+	secret, err := generateFeedSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	feed := model.CalendarFeed{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := firestoreClient.Collection("CalendarFeeds").Doc(userID).Set(ctx, feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetFeedUserID หา userid จาก secret ของ feed
+func GetFeedUserID(ctx context.Context, firestoreClient *firestore.Client, secret string) (string, error) {
+	if secret == "" {
+		return "", ErrFeedNotFound
+	}
+
+	docs, err := firestoreClient.Collection("CalendarFeeds").Where("secret", "==", secret).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return "", err
+	}
+	if len(docs) == 0 {
+		return "", ErrFeedNotFound
+	}
+
+	var feed model.CalendarFeed
+	if err := docs[0].DataTo(&feed); err != nil {
+		return "", err
+	}
+	return feed.UserID, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"myapp/apperror"
+	"myapp/model"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxBoardColumns จำนวนคอลัมน์สูงสุดต่อบอร์ด
+	MaxBoardColumns = 20
+	// PositionStep ระยะห่างของ position เมื่อจัดเรียงคอลัมน์ใหม่
+	PositionStep = 1024
+)
+
+var ErrUnknownStatus = apperror.New(apperror.CodeTaskUnknownStatus)
+
+// DefaultColumns คอลัมน์เริ่มต้น ใช้ id เดียวกับค่า status เดิม "0"/"1"/"2"
+func DefaultColumns() []model.BoardColumn {
+	return []model.BoardColumn{
+		{ColumnID: model.StatusPending, Name: "Pending"},
+		{ColumnID: model.StatusInProgress, Name: "In progress"},
+		{ColumnID: model.StatusCompleted, Name: "Completed", Done: true},
+	}
+}
+
+// BoardColumns คอลัมน์ของบอร์ดตามลำดับ บอร์ดเก่าที่ยังไม่ได้ตั้งค่าจะได้คอลัมน์เริ่มต้น
+func BoardColumns(board *model.Board) []model.BoardColumn {
+	if len(board.Columns) == 0 {
+		return DefaultColumns()
+	}
+	return board.Columns
+}
+
+// FindColumn คืนค่าคอลัมน์และลำดับของคอลัมน์ที่มี id ตรงกับ status
+func FindColumn(columns []model.BoardColumn, status model.TaskStatus) (model.BoardColumn, int, bool) {
+	for i, column := range columns {
+		if column.ColumnID == status {
+			return column, i, true
+		}
+	}
+	return model.BoardColumn{}, -1, false
+}
+
+// ResolveStatus ตรวจว่า status เป็นคอลัมน์ของบอร์ด ถ้าไม่ส่งมาจะใช้คอลัมน์แรก
+func ResolveStatus(board *model.Board, status model.TaskStatus) (model.BoardColumn, error) {
+	columns := BoardColumns(board)
+	if status == "" {
+		return columns[0], nil
+	}
+	column, _, ok := FindColumn(columns, status)
+	if !ok {
+		return model.BoardColumn{}, ErrUnknownStatus
+	}
+	return column, nil
+}
+
+// ColumnForLegacyStatus แปลงค่า "0"/"1"/"2" (เช่นจากไฟล์ import) เป็นคอลัมน์ของบอร์ด
+// ถ้าบอร์ดไม่มีคอลัมน์ id นั้น "2" จะไปคอลัมน์ done แรก ค่าอื่นไปคอลัมน์แรก
+func ColumnForLegacyStatus(board *model.Board, status model.TaskStatus) model.BoardColumn {
+	columns := BoardColumns(board)
+	if column, _, ok := FindColumn(columns, status); ok {
+		return column
+	}
+	if status == model.StatusCompleted {
+		for _, column := range columns {
+			if column.Done {
+				return column
+			}
+		}
+		return columns[len(columns)-1]
+	}
+	return columns[0]
+}
+
+// IsTaskCompleted task ที่อยู่ในคอลัมน์ done จะมี CompletedAt เสมอ
+func IsTaskCompleted(task *model.Tasks) bool {
+	return task.CompletedAt != nil
+}
+
+// InitialPosition position ของ task ใหม่ ใช้เวลาที่สร้างเพื่อให้ต่อท้ายคอลัมน์โดยไม่ต้องอ่านข้อมูล
+func InitialPosition(createdAt time.Time) float64 {
+	return float64(createdAt.UnixMilli())
+}
+
+// SortByPosition เรียง task ตาม position แล้วตามเวลาที่สร้าง (task เก่าที่ position เป็น 0 จะเรียงตามเวลาสร้าง)
+func SortByPosition(tasks []model.Tasks) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
+}
+
+// PositionBetween คำนวณ position ระหว่าง task ก่อนหน้าและถัดไป (nil = ไม่มี)
+// คืนค่า false เมื่อช่องว่างเล็กเกินไป ต้องจัดเรียงคอลัมน์ใหม่
+func PositionBetween(prev, next *float64) (float64, bool) {
+	switch {
+	case prev == nil && next == nil:
+		return PositionStep, true
+	case next == nil:
+		return *prev + PositionStep, true
+	case prev == nil:
+		return *next - PositionStep, true
+	}
+	mid := *prev + (*next-*prev)/2
+	return mid, *prev < mid && mid < *next
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPositionBetween(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name   string
+		prev   *float64
+		next   *float64
+		want   float64
+		wantOK bool
+	}{
+		{name: "empty column", want: PositionStep, wantOK: true},
+		{name: "after the last task", prev: f(2048), want: 2048 + PositionStep, wantOK: true},
+		{name: "before the first task", next: f(2048), want: 2048 - PositionStep, wantOK: true},
+		{name: "between two tasks", prev: f(1024), next: f(2048), want: 1536, wantOK: true},
+		{name: "negative positions", prev: f(-3), next: f(-1), want: -2, wantOK: true},
+		{name: "equal positions need a rebalance", prev: f(5), next: f(5), want: 5, wantOK: false},
+		{name: "gap too small", prev: f(1), next: f(math.Nextafter(1, 2)), want: 1, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PositionBetween(tt.prev, tt.next)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("PositionBetween() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
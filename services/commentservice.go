@@ -0,0 +1,49 @@
+package services
+
+import (
+	"myapp/model"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+)
+
+func CommentCollection(firestoreClient *firestore.Client, taskID string) *firestore.CollectionRef {
+	return firestoreClient.Collection("Tasks").Doc(taskID).Collection("Comments")
+}
+
+// mentionPattern จับข้อความหลัง @ ที่อยู่ต้นข้อความหรือหลังช่องว่าง เช่น "@somchai" หรือ "@somchai@mail.com"
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([^\s@]+(?:@[^\s@]+)?)`)
+
+// ParseMentions หา userid ของสมาชิกบอร์ดที่ถูก @mention ในข้อความ
+// เทียบกับอีเมล, ส่วนหน้าของอีเมล หรือชื่อที่ตัดช่องว่างออก (ไม่สนตัวพิมพ์เล็กใหญ่)
+func ParseMentions(text string, members []model.User) []string {
+	lookup := make(map[string]string)
+	for _, member := range members {
+		email := strings.ToLower(member.Email)
+		if email != "" {
+			lookup[email] = member.UserID
+			if at := strings.Index(email, "@"); at > 0 {
+				if _, taken := lookup[email[:at]]; !taken {
+					lookup[email[:at]] = member.UserID
+				}
+			}
+		}
+		if name := strings.ToLower(strings.Join(strings.Fields(member.Name), "")); name != "" {
+			if _, taken := lookup[name]; !taken {
+				lookup[name] = member.UserID
+			}
+		}
+	}
+
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		token := strings.ToLower(strings.TrimRight(match[1], ".,!?:;)\"'"))
+		if userID, ok := lookup[token]; ok && !seen[userID] {
+			seen[userID] = true
+			mentions = append(mentions, userID)
+		}
+	}
+	return mentions
+}
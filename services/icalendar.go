@@ -0,0 +1,174 @@
+package services
+
+import (
+	"myapp/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalDateLayout     = "20060102"
+	icalDateTimeLayout = "20060102T150405Z"
+	icalLineLimit      = 75
+)
+
+var icalEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// icalWriter สร้างข้อความ iCalendar (RFC 5545) โดยจัดการ CRLF และการพับบรรทัดให้อัตโนมัติ
+type icalWriter struct {
+	b strings.Builder
+}
+
+func (w *icalWriter) line(s string) {
+	// พับบรรทัดที่ยาวเกิน 75 octets โดยไม่ตัดกลางตัวอักษร UTF-8 (เช่นภาษาไทย)
+	// บรรทัดต่อเนื่องขึ้นต้นด้วยช่องว่าง จึงเหลือพื้นที่ 74 octets
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.b.WriteString(s[:cut])
+		w.b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = icalLineLimit - 1
+	}
+	w.b.WriteString(s)
+	w.b.WriteString("\r\n")
+}
+
+func (w *icalWriter) text(name, value string) {
+	if value == "" {
+		return
+	}
+	w.line(name + ":" + icalEscaper.Replace(value))
+}
+
+func icalUTC(t time.Time) string {
+	return t.UTC().Format(icalDateTimeLayout)
+}
+
+// ICalRRule แปลง RecurringPattern เป็น RRULE
+func ICalRRule(pattern string) string {
+	switch pattern {
+	case PatternDaily:
+		return "FREQ=DAILY"
+	case PatternWeekly:
+		return "FREQ=WEEKLY"
+	case PatternMonthly:
+		return "FREQ=MONTHLY"
+	case PatternYearly:
+		return "FREQ=YEARLY"
+	}
+	return ""
+}
+
+// ICalPriority แปลง priority ของ task ("1" ต่ำ - "3" สูง) เป็นค่า PRIORITY ของ iCalendar (1 สูงสุด - 9 ต่ำสุด)
+func ICalPriority(priority model.Priority) string {
+	switch priority {
+	case model.PriorityHigh:
+		return "1"
+	case model.PriorityMedium:
+		return "5"
+	case model.PriorityLow:
+		return "9"
+	}
+	return ""
+}
+
+// icalTodoStatus task ที่อยู่ในคอลัมน์ done เป็น COMPLETED ส่วนคอลัมน์ "1" (in progress เริ่มต้น) เป็น IN-PROCESS
+func icalTodoStatus(task *model.Tasks) string {
+	switch {
+	case IsTaskCompleted(task):
+		return "COMPLETED"
+	case task.Status == model.StatusInProgress:
+		return "IN-PROCESS"
+	}
+	return "NEEDS-ACTION"
+}
+
+// RenderICS สร้าง calendar feed จาก task ที่มีวันครบกำหนด
+// - task แบบทั้งวัน หรือที่มีวันเริ่มต้น จะเป็น VEVENT
+// - task อื่นๆ จะเป็น VTODO ที่มี DUE
+// reminder (BeforeDueDate) จะถูกแปลงเป็น VALARM และ RecurringPattern เป็น RRULE
+func RenderICS(calendarName string, tasks []model.Tasks, notifications map[string]model.Notification, loc *time.Location) string {
+	var w icalWriter
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//MyDayPlanner//Tasks//TH")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.text("X-WR-CALNAME", calendarName)
+	w.text("X-WR-TIMEZONE", loc.String())
+
+	now := time.Now()
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		notification, hasReminder := notifications[task.TaskID]
+
+		component := "VTODO"
+		if task.AllDay || task.StartDate != nil {
+			component = "VEVENT"
+		}
+
+		w.line("BEGIN:" + component)
+		w.line("UID:" + task.TaskID + "@mydayplanner")
+		stamp := task.UpdatedAt
+		if stamp.IsZero() {
+			stamp = now
+		}
+		w.line("DTSTAMP:" + icalUTC(stamp))
+		w.text("SUMMARY", task.TaskName)
+		w.text("DESCRIPTION", task.Description)
+		if p := ICalPriority(task.Priority); p != "" {
+			w.line("PRIORITY:" + p)
+		}
+
+		switch {
+		case component == "VEVENT" && task.AllDay:
+			start := *task.DueDate
+			if task.StartDate != nil {
+				start = *task.StartDate
+			}
+			// วันที่ของ task แบบทั้งวันไม่ขึ้นกับ timezone จึงไม่แปลงตาม loc
+			w.line("DTSTART;VALUE=DATE:" + start.UTC().Format(icalDateLayout))
+			w.line("DTEND;VALUE=DATE:" + task.DueDate.UTC().AddDate(0, 0, 1).Format(icalDateLayout))
+		case component == "VEVENT":
+			w.line("DTSTART:" + icalUTC(*task.StartDate))
+			w.line("DTEND:" + icalUTC(*task.DueDate))
+		default:
+			w.line("DUE:" + icalUTC(*task.DueDate))
+			w.line("STATUS:" + icalTodoStatus(&task))
+			if task.CompletedAt != nil {
+				w.line("COMPLETED:" + icalUTC(*task.CompletedAt))
+			}
+		}
+
+		if hasReminder {
+			if rule := ICalRRule(NormalizePattern(notification.RecurringPattern)); rule != "" {
+				w.line("RRULE:" + rule)
+			}
+			if notification.BeforeDueDate != nil {
+				w.line("BEGIN:VALARM")
+				w.line("ACTION:DISPLAY")
+				w.text("DESCRIPTION", task.TaskName)
+				w.line("TRIGGER;VALUE=DATE-TIME:" + icalUTC(*notification.BeforeDueDate))
+				w.line("END:VALARM")
+			}
+		}
+
+		w.line("END:" + component)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.b.String()
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"myapp/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportRows จำกัดจำนวนรายการต่อการ import หนึ่งครั้ง
+// (task + reminder = 2 writes ต่อแถว และ transaction รองรับได้ไม่เกิน 500 writes)
+const MaxImportRows = 250
+
+var ErrTooManyRows = fmt.Errorf("import is limited to %d tasks", MaxImportRows)
+
+// ImportRow ผลการแปลงข้อมูลหนึ่งรายการจากไฟล์ import
+type ImportRow struct {
+	Line     int
+	Task     model.Tasks
+	Reminder *model.Notification
+	Errors   []string
+}
+
+func (r *ImportRow) addError(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// ParseImportStatus รับได้ทั้ง "0"/"1"/"2" และชื่อสถานะ
+func ParseImportStatus(value string) (model.TaskStatus, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "pending", "todo", "needs-action":
+		return model.StatusPending, true
+	case "1", "in progress", "in-progress", "in-process", "doing":
+		return model.StatusInProgress, true
+	case "2", "completed", "complete", "done":
+		return model.StatusCompleted, true
+	}
+	return "", false
+}
+
+// ParseImportPriority รับได้ทั้ง "1"/"2"/"3" และ low/medium/high
+func ParseImportPriority(value string) (model.Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return model.PriorityNone, true
+	case "1", "low":
+		return model.PriorityLow, true
+	case "2", "medium":
+		return model.PriorityMedium, true
+	case "3", "high":
+		return model.PriorityHigh, true
+	}
+	return "", false
+}
+
+// ---------- CSV ----------
+
+var csvColumns = []string{"name", "description", "status", "priority", "duedate", "reminder"}
+
+// ParseCSVImport อ่านไฟล์ CSV ที่มี header: name, description, status, priority, duedate, reminder
+// duedate ที่เป็นรูปแบบ YYYY-MM-DD จะถือเป็น task แบบทั้งวัน
+func ParseCSVImport(r io.Reader, loc *time.Location) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV file is empty or unreadable")
+	}
+
+	index := make(map[string]int)
+	for i, col := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))] = i
+	}
+	if _, ok := index["name"]; !ok {
+		return nil, fmt.Errorf("CSV header must contain a name column (%s)", strings.Join(csvColumns, ", "))
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, ImportRow{Line: parseErr.StartLine, Errors: []string{"malformed CSV row"}})
+				continue
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) >= MaxImportRows {
+			return nil, ErrTooManyRows
+		}
+
+		get := func(col string) string {
+			if i, ok := index[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := ImportRow{Line: line}
+		row.Task.TaskName = get("name")
+		row.Task.Description = get("description")
+
+		if status, ok := ParseImportStatus(get("status")); ok {
+			row.Task.Status = status
+		} else {
+			row.addError("invalid status %q", get("status"))
+		}
+		if priority, ok := ParseImportPriority(get("priority")); ok {
+			row.Task.Priority = priority
+		} else {
+			row.addError("invalid priority %q", get("priority"))
+		}
+
+		if due := get("duedate"); due != "" {
+			_, dateErr := time.ParseInLocation("2006-01-02", due, loc)
+			row.Task.AllDay = dateErr == nil
+			dueDate, err := ParseTaskTime(due, row.Task.AllDay, loc)
+			if err != nil {
+				row.addError("invalid duedate %q", due)
+			}
+			row.Task.DueDate = dueDate
+		}
+
+		if reminder := get("reminder"); reminder != "" {
+			remindAt, err := ParseTaskTime(reminder, false, loc)
+			if err != nil {
+				row.addError("invalid reminder %q", reminder)
+			} else {
+				row.Reminder = &model.Notification{DueDate: TaskDueAt(&row.Task, loc), BeforeDueDate: remindAt}
+			}
+		}
+
+		validateImportRow(&row)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ---------- iCalendar ----------
+
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+var icalUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+// readICalLines อ่านไฟล์และรวมบรรทัดที่ถูกพับ (ขึ้นต้นด้วยช่องว่างหรือ tab) พร้อมเก็บเลขบรรทัดเริ่มต้น
+func readICalLines(r io.Reader) ([]string, []int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	var numbers []int
+	n := 0
+	for scanner.Scan() {
+		n++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += text[1:]
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		lines = append(lines, text)
+		numbers = append(numbers, n)
+	}
+	return lines, numbers, scanner.Err()
+}
+
+func parseICalProperty(line string) (icalProperty, bool) {
+	colon := -1
+	inQuote := false
+	for i, ch := range line {
+		if ch == '"' {
+			inQuote = !inQuote
+		}
+		if ch == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icalProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProperty{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string),
+		Value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if eq := strings.Index(param, "="); eq > 0 {
+			prop.Params[strings.ToUpper(param[:eq])] = strings.Trim(param[eq+1:], `"`)
+		}
+	}
+	return prop, true
+}
+
+// parseICalTime แปลง DATE หรือ DATE-TIME (UTC, TZID หรือ floating ตาม loc)
+func parseICalTime(prop icalProperty, loc *time.Location) (time.Time, bool, error) {
+	// ค่าแบบ DATE เป็นวันที่ล้วน เก็บเป็นเที่ยงคืน UTC เหมือน task แบบทั้งวันที่สร้างผ่าน API
+	if prop.Params["VALUE"] == "DATE" || len(prop.Value) == 8 {
+		t, err := time.Parse("20060102", prop.Value)
+		return t, true, err
+	}
+	if strings.HasSuffix(prop.Value, "Z") {
+		t, err := time.Parse(icalDateTimeLayout, prop.Value)
+		return t, false, err
+	}
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", prop.Value, loc)
+	return t, false, err
+}
+
+var icalDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICalDuration แปลง duration ของ RFC 5545 เช่น "-PT15M" หรือ "-P1D"
+func parseICalDuration(value string) (time.Duration, error) {
+	m := icalDurationPattern.FindStringSubmatch(strings.ToUpper(value))
+	if m == nil || value == "P" || value == "-P" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+2])
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func icalPatternFromRRule(rule string) string {
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		if strings.HasPrefix(part, "FREQ=") {
+			switch strings.TrimPrefix(part, "FREQ=") {
+			case "DAILY":
+				return PatternDaily
+			case "WEEKLY":
+				return PatternWeekly
+			case "MONTHLY":
+				return PatternMonthly
+			case "YEARLY":
+				return PatternYearly
+			}
+		}
+	}
+	return ""
+}
+
+func icalPriority(value string) model.Priority {
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil || n == 0:
+		return model.PriorityNone
+	case n <= 4:
+		return model.PriorityHigh
+	case n == 5:
+		return model.PriorityMedium
+	default:
+		return model.PriorityLow
+	}
+}
+
+// ParseICSImport อ่าน VTODO และ VEVENT จากไฟล์ .ics
+func ParseICSImport(r io.Reader, loc *time.Location) ([]ImportRow, error) {
+	lines, numbers, err := readICalLines(r)
+	if err != nil {
+		return nil, errors.New("failed to read iCalendar file")
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, errors.New("file is not a valid iCalendar (missing BEGIN:VCALENDAR)")
+	}
+
+	var rows []ImportRow
+	var current *ImportRow
+	var component string
+	var inAlarm bool
+	var trigger *icalProperty
+	var start, due, end *time.Time
+	var pattern string
+
+	for i, line := range lines {
+		prop, ok := parseICalProperty(line)
+		if !ok {
+			if current != nil {
+				current.addError("malformed line %d", numbers[i])
+			}
+			continue
+		}
+
+		switch {
+		case prop.Name == "BEGIN" && (strings.EqualFold(prop.Value, "VTODO") || strings.EqualFold(prop.Value, "VEVENT")):
+			if len(rows) >= MaxImportRows {
+				return nil, ErrTooManyRows
+			}
+			component = strings.ToUpper(prop.Value)
+			current = &ImportRow{Line: numbers[i]}
+			current.Task.Status = model.StatusPending
+			start, due, end, trigger, pattern = nil, nil, nil, nil, ""
+			continue
+		case current == nil:
+			continue
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VALARM"):
+			inAlarm = true
+			continue
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VALARM"):
+			inAlarm = false
+			continue
+		case prop.Name == "END" && strings.EqualFold(prop.Value, component):
+			finishICalRow(current, start, due, end, trigger, pattern, loc)
+			validateImportRow(current)
+			rows = append(rows, *current)
+			current = nil
+			continue
+		}
+
+		if inAlarm {
+			if prop.Name == "TRIGGER" && trigger == nil {
+				p := prop
+				trigger = &p
+			}
+			continue
+		}
+
+		switch prop.Name {
+		case "SUMMARY":
+			current.Task.TaskName = strings.TrimSpace(icalUnescaper.Replace(prop.Value))
+		case "DESCRIPTION":
+			current.Task.Description = icalUnescaper.Replace(prop.Value)
+		case "STATUS":
+			if status, ok := ParseImportStatus(prop.Value); ok {
+				current.Task.Status = status
+			}
+		case "PRIORITY":
+			current.Task.Priority = icalPriority(prop.Value)
+		case "RRULE":
+			pattern = icalPatternFromRRule(prop.Value)
+			if pattern == "" {
+				current.addError("unsupported RRULE %q", prop.Value)
+			}
+		case "DTSTART", "DUE", "DTEND":
+			t, allDay, err := parseICalTime(prop, loc)
+			if err != nil {
+				current.addError("invalid %s %q", prop.Name, prop.Value)
+				continue
+			}
+			if allDay {
+				current.Task.AllDay = true
+			}
+			switch prop.Name {
+			case "DTSTART":
+				start = &t
+			case "DUE":
+				due = &t
+			case "DTEND":
+				end = &t
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("component starting at line %d is not closed", current.Line)
+	}
+	return rows, nil
+}
+
+// finishICalRow กำหนดวันเริ่มต้น/ครบกำหนดและ reminder จากข้อมูลที่อ่านได้
+// reminder ของ task แบบทั้งวันนับจากเที่ยงคืนตาม loc
+func finishICalRow(row *ImportRow, start, due, end *time.Time, trigger *icalProperty, pattern string, loc *time.Location) {
+	if due == nil && end != nil {
+		if row.Task.AllDay {
+			// DTEND แบบ DATE เป็นวันถัดจากวันสุดท้าย (exclusive)
+			last := end.AddDate(0, 0, -1)
+			end = &last
+		}
+		due = end
+	}
+	if due == nil {
+		due = start
+		start = nil
+	}
+	row.Task.StartDate = start
+	row.Task.DueDate = due
+
+	if trigger == nil && pattern == "" {
+		return
+	}
+	if row.Task.AllDay {
+		if due != nil {
+			t := AllDayIn(*due, loc)
+			due = &t
+		}
+		if start != nil {
+			t := AllDayIn(*start, loc)
+			start = &t
+		}
+	}
+	row.Reminder = &model.Notification{DueDate: due}
+	if pattern != "" {
+		p := pattern
+		row.Reminder.RecurringPattern = &p
+	}
+	if trigger == nil {
+		return
+	}
+
+	if trigger.Params["VALUE"] == "DATE-TIME" {
+		t, _, err := parseICalTime(*trigger, time.UTC)
+		if err != nil {
+			row.addError("invalid alarm TRIGGER %q", trigger.Value)
+			return
+		}
+		row.Reminder.BeforeDueDate = &t
+		return
+	}
+
+	offset, err := parseICalDuration(trigger.Value)
+	if err != nil {
+		row.addError("invalid alarm TRIGGER %q", trigger.Value)
+		return
+	}
+	anchor := due
+	if trigger.Params["RELATED"] == "START" && start != nil {
+		anchor = start
+	}
+	if anchor == nil {
+		row.addError("alarm TRIGGER requires a due date")
+		return
+	}
+	t := anchor.Add(offset)
+	row.Reminder.BeforeDueDate = &t
+}
+
+// validateImportRow ตรวจสอบความถูกต้องที่ใช้ร่วมกันทั้ง CSV และ iCalendar
+func validateImportRow(row *ImportRow) {
+	if row.Task.TaskName == "" {
+		row.addError("name is required")
+	}
+	if len(row.Task.TaskName) > 200 {
+		row.addError("name must not exceed 200 characters")
+	}
+	if row.Task.StartDate != nil && row.Task.DueDate != nil && row.Task.DueDate.Before(*row.Task.StartDate) {
+		row.addError("due date must not be before start date")
+	}
+	if row.Reminder != nil && row.Reminder.BeforeDueDate != nil {
+		if row.Task.DueDate == nil || row.Reminder.DueDate == nil {
+			row.addError("reminder requires a due date")
+		} else if row.Reminder.BeforeDueDate.After(*row.Reminder.DueDate) {
+			row.addError("reminder must not be after the due date")
+		}
+	}
+}
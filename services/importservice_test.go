@@ -0,0 +1,236 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"myapp/model"
+)
+
+func TestParseCSVImport(t *testing.T) {
+	bangkok := mustLoad(t, "Asia/Bangkok")
+
+	type wantRow struct {
+		name     string
+		status   model.TaskStatus
+		priority model.Priority
+		due      *time.Time
+		allDay   bool
+		remindAt *time.Time
+		errors   int
+	}
+	at := func(v time.Time) *time.Time { return &v }
+
+	tests := []struct {
+		name    string
+		csv     string
+		want    []wantRow
+		wantErr bool
+	}{
+		{
+			name: "all-day and timed rows",
+			csv: "\ufeffName,Status,Priority,DueDate,Reminder\n" +
+				"Pay rent,done,high,2024-03-12,2024-03-11T09:00\n" +
+				"Call,1,low,2024-03-12T15:00:00+07:00,\n",
+			want: []wantRow{
+				{name: "Pay rent", status: model.StatusCompleted, priority: model.PriorityHigh,
+					due: at(time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)), allDay: true,
+					remindAt: at(time.Date(2024, 3, 11, 9, 0, 0, 0, bangkok))},
+				{name: "Call", status: model.StatusInProgress, priority: model.PriorityLow,
+					due: at(time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC))},
+			},
+		},
+		{
+			name: "row errors are reported per row",
+			csv: "name,status,priority,duedate,reminder\n" +
+				",unknown,urgent,tomorrow,\n" +
+				"Late reminder,,,2024-03-12T09:00,2024-03-13T09:00\n",
+			want: []wantRow{
+				{errors: 4},
+				{name: "Late reminder", status: model.StatusPending,
+					due:      at(time.Date(2024, 3, 12, 9, 0, 0, 0, bangkok)),
+					remindAt: at(time.Date(2024, 3, 13, 9, 0, 0, 0, bangkok)), errors: 1},
+			},
+		},
+		{name: "missing name column", csv: "title,status\nx,0\n", wantErr: true},
+		{name: "empty file", csv: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseCSVImport(strings.NewReader(tt.csv), bangkok)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, want := range tt.want {
+				row := rows[i]
+				if len(row.Errors) != want.errors {
+					t.Fatalf("row %d: errors = %q, want %d", i, row.Errors, want.errors)
+				}
+				if want.errors > 0 && want.name == "" {
+					continue
+				}
+				if row.Task.TaskName != want.name || row.Task.Status != want.status || row.Task.Priority != want.priority {
+					t.Errorf("row %d: got %q/%q/%q, want %q/%q/%q", i, row.Task.TaskName, row.Task.Status, row.Task.Priority,
+						want.name, want.status, want.priority)
+				}
+				if row.Task.AllDay != want.allDay || !equalTime(row.Task.DueDate, want.due) {
+					t.Errorf("row %d: due = %v allday = %v, want %v allday = %v", i, row.Task.DueDate, row.Task.AllDay, want.due, want.allDay)
+				}
+				var remindAt *time.Time
+				if row.Reminder != nil {
+					remindAt = row.Reminder.BeforeDueDate
+				}
+				if !equalTime(remindAt, want.remindAt) {
+					t.Errorf("row %d: reminder = %v, want %v", i, remindAt, want.remindAt)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSVImportTooManyRows(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("name\n")
+	for i := 0; i <= MaxImportRows; i++ {
+		fmt.Fprintf(&b, "task %d\n", i)
+	}
+	if _, err := ParseCSVImport(strings.NewReader(b.String()), time.UTC); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("error = %v, want ErrTooManyRows", err)
+	}
+}
+
+func TestParseICSImport(t *testing.T) {
+	bangkok := mustLoad(t, "Asia/Bangkok")
+
+	ics := func(body string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + body + "END:VCALENDAR\r\n"
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		wantErr    bool
+		wantName   string
+		wantStart  *time.Time
+		wantDue    *time.Time
+		wantAllDay bool
+		wantRemind *time.Time
+		wantRule   string
+		wantStatus model.TaskStatus
+		wantErrors int
+	}{
+		{
+			name: "todo with UTC due and relative alarm",
+			file: ics("BEGIN:VTODO\r\nSUMMARY:Send inv\r\n oice\r\nDUE:20240312T020000Z\r\nSTATUS:COMPLETED\r\n" +
+				"BEGIN:VALARM\r\nTRIGGER:-PT30M\r\nEND:VALARM\r\nEND:VTODO\r\n"),
+			wantName:   "Send invoice",
+			wantDue:    ptrTime(time.Date(2024, 3, 12, 2, 0, 0, 0, time.UTC)),
+			wantRemind: ptrTime(time.Date(2024, 3, 12, 1, 30, 0, 0, time.UTC)),
+			wantStatus: model.StatusCompleted,
+		},
+		{
+			name: "all-day event uses the exclusive end date",
+			file: ics("BEGIN:VEVENT\r\nSUMMARY:Trip\r\nDTSTART;VALUE=DATE:20240310\r\nDTEND;VALUE=DATE:20240313\r\n" +
+				"RRULE:FREQ=YEARLY\r\nBEGIN:VALARM\r\nTRIGGER:-P1D\r\nEND:VALARM\r\nEND:VEVENT\r\n"),
+			wantName:   "Trip",
+			wantStart:  ptrTime(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+			wantDue:    ptrTime(time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)),
+			wantAllDay: true,
+			wantRemind: ptrTime(time.Date(2024, 3, 11, 0, 0, 0, 0, bangkok)),
+			wantRule:   PatternYearly,
+			wantStatus: model.StatusPending,
+		},
+		{
+			name:       "floating and TZID times",
+			file:       ics("BEGIN:VEVENT\r\nSUMMARY:Meet\\, plan\r\nDTSTART;TZID=UTC:20240312T090000\r\nDTEND:20240312T170000\r\nEND:VEVENT\r\n"),
+			wantName:   "Meet, plan",
+			wantStart:  ptrTime(time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC)),
+			wantDue:    ptrTime(time.Date(2024, 3, 12, 17, 0, 0, 0, bangkok)),
+			wantStatus: model.StatusPending,
+		},
+		{
+			name:       "invalid values are row errors",
+			file:       ics("BEGIN:VTODO\r\nDUE:notadate\r\nRRULE:FREQ=HOURLY\r\nEND:VTODO\r\n"),
+			wantStatus: model.StatusPending,
+			wantErrors: 3,
+		},
+		{name: "not an iCalendar file", file: "name,status\n", wantErr: true},
+		{name: "unclosed component", file: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:x\r\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseICSImport(strings.NewReader(tt.file), bangkok)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(rows) != 1 {
+				t.Fatalf("got %d rows, want 1", len(rows))
+			}
+			row := rows[0]
+			if len(row.Errors) != tt.wantErrors {
+				t.Fatalf("errors = %q, want %d", row.Errors, tt.wantErrors)
+			}
+			if row.Task.TaskName != tt.wantName || row.Task.Status != tt.wantStatus || row.Task.AllDay != tt.wantAllDay {
+				t.Errorf("task = %q/%q/allday %v, want %q/%q/allday %v", row.Task.TaskName, row.Task.Status, row.Task.AllDay,
+					tt.wantName, tt.wantStatus, tt.wantAllDay)
+			}
+			if tt.wantErrors > 0 {
+				return
+			}
+			if !equalTime(row.Task.StartDate, tt.wantStart) || !equalTime(row.Task.DueDate, tt.wantDue) {
+				t.Errorf("start/due = %v/%v, want %v/%v", row.Task.StartDate, row.Task.DueDate, tt.wantStart, tt.wantDue)
+			}
+			var remind *time.Time
+			var rule string
+			if row.Reminder != nil {
+				remind = row.Reminder.BeforeDueDate
+				rule = NormalizePattern(row.Reminder.RecurringPattern)
+			}
+			if !equalTime(remind, tt.wantRemind) || rule != tt.wantRule {
+				t.Errorf("reminder = %v %q, want %v %q", remind, rule, tt.wantRemind, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "-PT15M", want: -15 * time.Minute},
+		{value: "PT1H30M", want: 90 * time.Minute},
+		{value: "-P1D", want: -24 * time.Hour},
+		{value: "P1W", want: 7 * 24 * time.Hour},
+		{value: "-P1DT2H", want: -26 * time.Hour},
+		{value: "P", wantErr: true},
+		{value: "15M", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseICalDuration(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseICalDuration(%q) = %v, %v, want %v, wantErr %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
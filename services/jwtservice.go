@@ -2,16 +2,23 @@ package services
 
 import (
 	"crypto/sha256"
+	"errors"
+	"myapp/config"
 	"myapp/model"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func CreateAccessToken(userID string, role string) (string, error) {
-	hmacSampleSecret := []byte(os.Getenv("JWT_SECRET_KEY"))
+// ErrMissingSecret ป้องกันการเซ็น token ด้วย key ว่าง
+var ErrMissingSecret = errors.New("jwt secret is not configured")
+
+func CreateAccessToken(cfg config.JWTConfig, userID string, role string) (string, error) {
+	if cfg.AccessSecret == "" {
+		return "", ErrMissingSecret
+	}
+	hmacSampleSecret := []byte(cfg.AccessSecret)
 	claims := &model.AccessClaims{
 		UserID: userID,
 		Role:   role,
@@ -25,8 +32,11 @@ func CreateAccessToken(userID string, role string) (string, error) {
 	return token.SignedString(hmacSampleSecret)
 }
 
-func CreateRefreshToken(userID string) (string, error) {
-	refreshTokenSecret := []byte(os.Getenv("JWT_REFRESH_SECRET_KEY"))
+func CreateRefreshToken(cfg config.JWTConfig, userID string) (string, error) {
+	if cfg.RefreshSecret == "" {
+		return "", ErrMissingSecret
+	}
+	refreshTokenSecret := []byte(cfg.RefreshSecret)
 	claims := &model.AccessRefresh{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"myapp/apperror"
+	"myapp/model"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+)
+
+const (
+	MaxLabelsPerBoard = 50
+	MaxLabelsPerTask  = 10
+	MaxLabelFilter    = 30 // labelid ที่กรองใน query เดียวได้ (ขีดจำกัดของ array-contains-any)
+)
+
+var ErrLabelNotFound = apperror.New(apperror.CodeLabelNotFound)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func LabelCollection(firestoreClient *firestore.Client, boardID string) *firestore.CollectionRef {
+	return firestoreClient.Collection("Boards").Doc(boardID).Collection("Labels")
+}
+
+// NormalizeLabel ตรวจสอบชื่อและสีของ label คืนค่าที่ตัดช่องว่างและสีเป็นตัวพิมพ์เล็ก
+func NormalizeLabel(name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > 50 {
+		return "", "", apperror.Invalid("name must be between 1 and 50 characters")
+	}
+	color = strings.TrimSpace(color)
+	if !labelColorPattern.MatchString(color) {
+		return "", "", apperror.Invalid("color must be a hex color like #ff8800")
+	}
+	return name, strings.ToLower(color), nil
+}
+
+// GetLabels ดึง label ทั้งหมดของบอร์ดเรียงตามชื่อ
+func GetLabels(ctx context.Context, firestoreClient *firestore.Client, boardID string) ([]model.Label, error) {
+	docs, err := LabelCollection(firestoreClient, boardID).OrderBy("name", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]model.Label, 0, len(docs))
+	for _, doc := range docs {
+		var label model.Label
+		if err := doc.DataTo(&label); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// ParseIDList รวมรายการ id ที่ส่งมาหลายค่าหรือคั่นด้วยจุลภาค ตัดช่องว่างและค่าซ้ำออก
+// ใช้กับ ?label= และรายการ id ใน request body
+func ParseIDList(values []string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// FilterTasksByLabels คืนเฉพาะ task ที่มี label ใด label หนึ่งในรายการ
+// ถ้าไม่ได้ระบุ label จะคืนทุก task
+func FilterTasksByLabels(tasks []model.Tasks, labelIDs []string) []model.Tasks {
+	if len(labelIDs) == 0 {
+		return tasks
+	}
+
+	wanted := make(map[string]bool, len(labelIDs))
+	for _, id := range labelIDs {
+		wanted[id] = true
+	}
+
+	filtered := make([]model.Tasks, 0, len(tasks))
+	for _, task := range tasks {
+		for _, id := range task.Labels {
+			if wanted[id] {
+				filtered = append(filtered, task)
+				break
+			}
+		}
+	}
+	return filtered
+}
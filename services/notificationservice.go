@@ -0,0 +1,34 @@
+package services
+
+import (
+	"myapp/model"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+)
+
+// ประเภทของ UserNotification
+const (
+	NotificationMention    = "mention"
+	NotificationAssigned   = "assigned"
+	NotificationUnassigned = "unassigned"
+	NotificationReminder   = "reminder"
+)
+
+func UserNotificationCollection(firestoreClient *firestore.Client) *firestore.CollectionRef {
+	return firestoreClient.Collection("UserNotifications")
+}
+
+// NewUserNotification สร้างการแจ้งเตือนใหม่ที่ยังไม่ได้อ่าน
+func NewUserNotification(userID, notificationType, actorID, message string) model.UserNotification {
+	return model.UserNotification{
+		NotificationID: uuid.New().String(),
+		UserID:         userID,
+		Type:           notificationType,
+		ActorID:        actorID,
+		Message:        message,
+		Read:           "0",
+		CreatedAt:      time.Now(),
+	}
+}
@@ -3,44 +3,24 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math/rand"
+	"myapp/metrics"
 	"myapp/model"
+	"myapp/tracing"
+	"net"
 	"net/smtp"
-	"os"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
-	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/api/iterator"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func LoadEmailConfig() (*model.EmailConfig, error) {
-	// โหลด .env เฉพาะตอนรัน local (เมื่อ ENV "RENDER" ว่าง)
-	if os.Getenv("RENDER") == "" {
-		if err := godotenv.Load(); err != nil {
-			fmt.Println("Warning: .env file not loaded, fallback to OS env vars")
-		}
-	}
-
-	config := &model.EmailConfig{
-		Host:     os.Getenv("SMTP_HOST"),
-		Port:     os.Getenv("SMTP_PORT"),
-		Username: os.Getenv("SMTP_USERNAME"),
-		Password: os.Getenv("SMTP_PASSWORD"),
-	}
-
-	if config.Host == "" || config.Port == "" || config.Username == "" || config.Password == "" {
-		return nil, fmt.Errorf("missing required SMTP environment variables")
-	}
-
-	fmt.Printf("SMTP Config: Host=%s, Port=%s, Username=%s\n", config.Host, config.Port, config.Username)
-	return config, nil
-}
-
 // ฟังก์ชันตรวจสอบว่าอีเมลถูกบล็อกหรือไม่
 func IsEmailBlocked(c context.Context, firestoreClient *firestore.Client, email string, recordfirebase string) (bool, error) {
 	// เข้าถึง document ของ email ใน collection หลัก
@@ -63,6 +43,7 @@ func IsEmailBlocked(c context.Context, firestoreClient *firestore.Client, email
 		expiresAt, ok := blockData["expiresAt"].(time.Time)
 		if ok {
 			if time.Now().Before(expiresAt) {
+				metrics.OTPBlocked(recordfirebase, "blocked")
 				return true, nil
 			}
 
@@ -116,6 +97,7 @@ func CheckAndBlockIfNeeded(c context.Context, firestoreClient *firestore.Client,
 		if err != nil {
 			return false, err
 		}
+		metrics.OTPBlocked(record, "limit")
 		return true, nil
 	}
 
@@ -310,17 +292,24 @@ func GenerateEmailContent(OTP string, REF string) string {
 	return emailTemplate
 }
 
-func SendingEmail(to, subject, body string) error {
-	// Load SMTP configuration
-	config, err := LoadEmailConfig()
+// CheckSMTP ตรวจว่าเชื่อมต่อ SMTP server ได้ (ไม่ได้ยืนยันตัวตนหรือส่งอีเมลจริง)
+func CheckSMTP(ctx context.Context, config model.EmailConfig) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(config.Host, config.Port))
 	if err != nil {
-		return fmt.Errorf("config loading error: %w", err)
+		return err
 	}
+	return conn.Close()
+}
+
+// SendingEmail ส่งอีเมล HTML ผ่าน SMTP ตาม config ที่โหลดไว้ตอนเริ่มต้น
+func SendingEmail(ctx context.Context, config model.EmailConfig, to, subject, body string) (err error) {
+	ctx, span := tracing.Start(ctx, "smtp.SendMail", attribute.String("smtp.host", config.Host))
+	defer func() { tracing.End(span, err) }()
 
 	// Validate SMTP configuration
 	if config.Host == "" || config.Port == "" || config.Username == "" || config.Password == "" {
-		return fmt.Errorf("incomplete SMTP configuration: host=%q, port=%q, username=%q",
-			config.Host, config.Port, config.Username)
+		return fmt.Errorf("incomplete SMTP configuration: host=%q, port=%q", config.Host, config.Port)
 	}
 
 	// Set up authentication and server address
@@ -337,12 +326,12 @@ func SendingEmail(to, subject, body string) error {
 		body
 
 	// Send email with better error handling
-	fmt.Printf("Sending email to %s via %s...\n", to, addr)
+	slog.DebugContext(ctx, "sending email", "to", to, "smtp_addr", addr)
 	err = smtp.SendMail(addr, auth, from, []string{to}, []byte(message))
 	if err != nil {
 		return fmt.Errorf("SMTP send error: %w", err)
 	}
 
-	fmt.Println("Email sent successfully")
+	slog.DebugContext(ctx, "email sent", "to", to)
 	return nil
 }
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+// รูปแบบการทำซ้ำที่รองรับใน RecurringPattern ของ Notification
+const (
+	PatternDaily   = "daily"
+	PatternWeekly  = "weekly"
+	PatternMonthly = "monthly"
+	PatternYearly  = "yearly"
+)
+
+// maxOccurrences กันไม่ให้ขยายรายการซ้ำมากเกินไปในช่วงเวลาที่ยาว
+const maxOccurrences = 1000
+
+func NormalizePattern(pattern *string) string {
+	if pattern == nil {
+		return ""
+	}
+	p := strings.ToLower(strings.TrimSpace(*pattern))
+	switch p {
+	case PatternDaily, PatternWeekly, PatternMonthly, PatternYearly:
+		return p
+	}
+	return ""
+}
+
+// addInterval เลื่อนเวลา base ไป n รอบตาม pattern โดยคำนวณจากวันที่ตาม loc
+// เพื่อไม่ให้เวลาเพี้ยนเมื่อผ่านช่วง daylight saving
+func addInterval(base time.Time, pattern string, n int, loc *time.Location) time.Time {
+	local := base.In(loc)
+	switch pattern {
+	case PatternDaily:
+		return local.AddDate(0, 0, n)
+	case PatternWeekly:
+		return local.AddDate(0, 0, 7*n)
+	case PatternMonthly:
+		return addMonthsClamped(local, n, loc)
+	case PatternYearly:
+		return addMonthsClamped(local, 12*n, loc)
+	}
+	return local
+}
+
+// addMonthsClamped เลื่อนเดือนโดยปัดวันที่ให้ไม่เกินวันสุดท้ายของเดือน (เช่น 31 ม.ค. -> 28 ก.พ.)
+func addMonthsClamped(t time.Time, months int, loc *time.Location) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// Occurrences คืนค่าเวลาที่เกิดซ้ำทั้งหมดของ base ที่อยู่ในช่วง [from, to)
+func Occurrences(base time.Time, pattern string, from, to time.Time, loc *time.Location) []time.Time {
+	if pattern == "" {
+		if !base.Before(from) && base.Before(to) {
+			return []time.Time{base}
+		}
+		return nil
+	}
+
+	var result []time.Time
+	for n := 0; n < maxOccurrences; n++ {
+		t := addInterval(base, pattern, n, loc)
+		if !t.Before(to) {
+			break
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		// ข้ามรอบที่อยู่ก่อนช่วงเวลาสำหรับ pattern รายวัน/รายสัปดาห์
+		if n == 0 && t.Before(from) && (pattern == PatternDaily || pattern == PatternWeekly) {
+			step := 24 * time.Hour
+			if pattern == PatternWeekly {
+				step *= 7
+			}
+			if skip := int(from.Sub(t)/step) - 1; skip > 0 {
+				n += skip
+			}
+		}
+	}
+	return result
+}
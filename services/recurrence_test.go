@@ -0,0 +1,112 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizePattern(t *testing.T) {
+	tests := []struct {
+		in   *string
+		want string
+	}{
+		{in: nil, want: ""},
+		{in: ptr(" Weekly "), want: PatternWeekly},
+		{in: ptr("YEARLY"), want: PatternYearly},
+		{in: ptr("hourly"), want: ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizePattern(tt.in); got != tt.want {
+			t.Errorf("NormalizePattern(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	utc := time.UTC
+	day := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, utc) }
+
+	tests := []struct {
+		name    string
+		base    time.Time
+		pattern string
+		from    time.Time
+		to      time.Time
+		loc     *time.Location
+		want    []time.Time
+	}{
+		{
+			name: "single occurrence inside the range",
+			base: day(2024, 3, 5, 9), from: day(2024, 3, 1, 0), to: day(2024, 3, 8, 0), loc: utc,
+			want: []time.Time{day(2024, 3, 5, 9)},
+		},
+		{
+			name: "single occurrence at the exclusive end",
+			base: day(2024, 3, 8, 0), from: day(2024, 3, 1, 0), to: day(2024, 3, 8, 0), loc: utc,
+		},
+		{
+			name: "daily starting before the range",
+			base: day(2024, 1, 1, 9), pattern: PatternDaily, from: day(2024, 3, 1, 0), to: day(2024, 3, 4, 0), loc: utc,
+			want: []time.Time{day(2024, 3, 1, 9), day(2024, 3, 2, 9), day(2024, 3, 3, 9)},
+		},
+		{
+			name: "weekly",
+			base: day(2024, 3, 1, 9), pattern: PatternWeekly, from: day(2024, 3, 1, 0), to: day(2024, 3, 20, 0), loc: utc,
+			want: []time.Time{day(2024, 3, 1, 9), day(2024, 3, 8, 9), day(2024, 3, 15, 9)},
+		},
+		{
+			name: "monthly clamps to the last day of the month",
+			base: day(2024, 1, 31, 9), pattern: PatternMonthly, from: day(2024, 1, 1, 0), to: day(2024, 5, 1, 0), loc: utc,
+			want: []time.Time{day(2024, 1, 31, 9), day(2024, 2, 29, 9), day(2024, 3, 31, 9), day(2024, 4, 30, 9)},
+		},
+		{
+			name: "yearly on leap day",
+			base: day(2024, 2, 29, 9), pattern: PatternYearly, from: day(2024, 1, 1, 0), to: day(2026, 1, 1, 0), loc: utc,
+			want: []time.Time{day(2024, 2, 29, 9), day(2025, 2, 28, 9)},
+		},
+		{
+			name: "base after the range",
+			base: day(2024, 4, 1, 9), pattern: PatternDaily, from: day(2024, 3, 1, 0), to: day(2024, 3, 4, 0), loc: utc,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Occurrences(tt.base, tt.pattern, tt.from, tt.to, tt.loc)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrencesKeepsLocalTimeAcrossDST(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+	base := time.Date(2024, 3, 9, 9, 0, 0, 0, loc)
+	got := Occurrences(base, PatternDaily, base, base.AddDate(0, 0, 3), loc)
+
+	var hours []int
+	for _, at := range got {
+		hours = append(hours, at.In(loc).Hour())
+	}
+	if want := []int{9, 9, 9}; !reflect.DeepEqual(hours, want) {
+		t.Fatalf("local hours = %v, want %v", hours, want)
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s not available: %v", name, err)
+	}
+	return loc
+}
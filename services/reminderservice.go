@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"myapp/mailer"
+	"myapp/metrics"
+	"myapp/model"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errReminderNotDue reminder ถูกส่งหรือแก้ไขไปแล้วระหว่างรอบ (เช่นโดย instance อื่น)
+var errReminderNotDue = errors.New("reminder is not due")
+
+// ReminderFireTime เวลาที่ต้องแจ้งเตือนครั้งถัดไป snooze มาก่อน แล้วจึงเป็น beforeduedate หรือ duedate
+// reminder ที่ทำซ้ำจะใช้รอบแรกที่อยู่หลัง lastsentat
+func ReminderFireTime(reminder *model.Notification, loc *time.Location) *time.Time {
+	if reminder.Snooze != nil {
+		return reminder.Snooze
+	}
+	base := reminder.BeforeDueDate
+	if base == nil {
+		base = reminder.DueDate
+	}
+	if base == nil {
+		return nil
+	}
+
+	pattern := NormalizePattern(reminder.RecurringPattern)
+	if pattern == "" || reminder.LastSentAt == nil || base.After(*reminder.LastSentAt) {
+		return base
+	}
+	for n := 1; n < maxOccurrences; n++ {
+		if next := addInterval(*base, pattern, n, loc); next.After(*reminder.LastSentAt) {
+			return &next
+		}
+	}
+	return nil
+}
+
+// ShiftReminder เลื่อน reminder ที่ผูกกับวันครบกำหนดเดิมของ task ไปตามวันครบกำหนดใหม่
+// beforeduedate เลื่อนเท่ากันเพื่อให้ห่างจากวันครบกำหนดเท่าเดิม reminder ที่ผู้ใช้ตั้งเวลาเองจะไม่ถูกแตะ
+// send กลับเป็น "0" เฉพาะเมื่อเวลาแจ้งเตือนใหม่ยังไม่ผ่านไป คืนค่าเท็จถ้าไม่มีอะไรเปลี่ยน
+func ShiftReminder(reminder *model.Notification, oldDue, newDue *time.Time, now time.Time) bool {
+	if oldDue == nil || newDue == nil || reminder.DueDate == nil || !reminder.DueDate.Equal(*oldDue) {
+		return false
+	}
+	offset := newDue.Sub(*oldDue)
+	if offset == 0 {
+		return false
+	}
+
+	due := *newDue
+	reminder.DueDate = &due
+	fireAt := due
+	if reminder.BeforeDueDate != nil {
+		before := reminder.BeforeDueDate.Add(offset)
+		reminder.BeforeDueDate = &before
+		fireAt = before
+	}
+	if fireAt.After(now) {
+		reminder.Send = "0"
+	}
+	return true
+}
+
+// DeliverDueReminders ส่ง reminder ที่ถึงเวลาแล้วเป็นการแจ้งเตือนในแอปและอีเมลถึงผู้สร้างและผู้รับผิดชอบ task
+// แต่ละรายการทำใน transaction ของตัวเอง จึงรันพร้อมกันหลาย instance ได้โดยไม่ส่งซ้ำ
+func DeliverDueReminders(ctx context.Context, firestoreClient *firestore.Client, mail *mailer.Queue, now time.Time) (int, error) {
+	docs, err := firestoreClient.Collection("NotificationTasks").Where("send", "==", "0").Documents(ctx).GetAll()
+	if err != nil || len(docs) == 0 {
+		return 0, err
+	}
+
+	reminders := make([]model.Notification, len(docs))
+	var taskRefs []*firestore.DocumentRef
+	seen := make(map[string]bool)
+	for i, doc := range docs {
+		if err := doc.DataTo(&reminders[i]); err != nil {
+			return 0, err
+		}
+		if taskID := reminders[i].TaskID; taskID != "" && !seen[taskID] {
+			seen[taskID] = true
+			taskRefs = append(taskRefs, firestoreClient.Collection("Tasks").Doc(taskID))
+		}
+	}
+	locations, err := reminderLocations(ctx, firestoreClient, taskRefs)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+		loc, ok := locations[reminders[i].TaskID]
+		if !ok {
+			loc, _ = LoadLocation(DefaultTimezone)
+		}
+		if at := ReminderFireTime(&reminders[i], loc); at == nil || at.After(now) {
+			continue
+		}
+
+		task, recipients, err := deliverReminder(ctx, firestoreClient, doc.Ref, loc, now)
+		if err != nil {
+			if !errors.Is(err, errReminderNotDue) {
+				slog.ErrorContext(ctx, "failed to deliver reminder", "reminder_id", doc.Ref.ID, "error", err)
+			}
+			continue
+		}
+		delivered++
+		metrics.RemindersDelivered(1)
+		if len(recipients) > 0 {
+			emailReminder(ctx, firestoreClient, mail, task, recipients)
+		}
+	}
+	return delivered, nil
+}
+
+// reminderLocations timezone ของผู้สร้างแต่ละ task ใช้คำนวณรอบของ reminder ที่ทำซ้ำ
+func reminderLocations(ctx context.Context, firestoreClient *firestore.Client, taskRefs []*firestore.DocumentRef) (map[string]*time.Location, error) {
+	defaultLoc, _ := LoadLocation(DefaultTimezone)
+	if len(taskRefs) == 0 {
+		return nil, nil
+	}
+
+	taskDocs, err := firestoreClient.GetAll(ctx, taskRefs)
+	if err != nil {
+		return nil, err
+	}
+	creators := make(map[string]string)
+	seen := make(map[string]bool)
+	var userRefs []*firestore.DocumentRef
+	for _, doc := range taskDocs {
+		if !doc.Exists() {
+			continue
+		}
+		createdBy, _ := doc.Data()["createdby"].(string)
+		if createdBy == "" {
+			continue
+		}
+		creators[doc.Ref.ID] = createdBy
+		if !seen[createdBy] {
+			seen[createdBy] = true
+			userRefs = append(userRefs, firestoreClient.Collection("Users").Doc(createdBy))
+		}
+	}
+
+	userLocs := make(map[string]*time.Location)
+	if len(userRefs) > 0 {
+		userDocs, err := firestoreClient.GetAll(ctx, userRefs)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range userDocs {
+			var user model.User
+			if doc.Exists() && doc.DataTo(&user) == nil {
+				userLocs[doc.Ref.ID] = UserLocation(&user)
+			}
+		}
+	}
+
+	locations := make(map[string]*time.Location, len(taskRefs))
+	for _, ref := range taskRefs {
+		locations[ref.ID] = defaultLoc
+		if loc, ok := userLocs[creators[ref.ID]]; ok {
+			locations[ref.ID] = loc
+		}
+	}
+	return locations, nil
+}
+
+func deliverReminder(ctx context.Context, firestoreClient *firestore.Client, ref *firestore.DocumentRef, loc *time.Location, now time.Time) (*model.Tasks, []string, error) {
+	var task *model.Tasks
+	var recipients []string
+	err := firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		task, recipients = nil, nil // transaction อาจถูกเรียกซ้ำ
+
+		docSnap, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return errReminderNotDue
+			}
+			return err
+		}
+		var reminder model.Notification
+		if err := docSnap.DataTo(&reminder); err != nil {
+			return err
+		}
+		if at := ReminderFireTime(&reminder, loc); reminder.Send != "0" || at == nil || at.After(now) {
+			return errReminderNotDue
+		}
+
+		taskSnap, err := tx.Get(firestoreClient.Collection("Tasks").Doc(reminder.TaskID))
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				// task ถูกลบไปแล้ว ไม่ต้องแจ้งเตือนอีก
+				return tx.Update(ref, []firestore.Update{{Path: "send", Value: "1"}, {Path: "updatedat", Value: now}})
+			}
+			return err
+		}
+		var current model.Tasks
+		if err := taskSnap.DataTo(&current); err != nil {
+			return err
+		}
+
+		if !IsTaskCompleted(&current) {
+			recipients = reminderRecipients(&current)
+			for _, id := range recipients {
+				notification := NewUserNotification(id, NotificationReminder, "", fmt.Sprintf("Reminder: %q", current.TaskName))
+				notification.BoardID = current.BoardID
+				notification.TaskID = current.TaskID
+				if err := tx.Create(UserNotificationCollection(firestoreClient).Doc(notification.NotificationID), notification); err != nil {
+					return err
+				}
+			}
+		}
+		task = &current
+
+		updates := []firestore.Update{
+			{Path: "snooze", Value: firestore.Delete},
+			{Path: "lastsentat", Value: now},
+			{Path: "updatedat", Value: now},
+		}
+		if NormalizePattern(reminder.RecurringPattern) == "" {
+			updates = append(updates, firestore.Update{Path: "send", Value: "1"})
+		}
+		return tx.Update(ref, updates)
+	})
+	return task, recipients, err
+}
+
+// reminderRecipients ผู้สร้าง task และผู้รับผิดชอบทั้งหมด (ไม่ซ้ำกัน)
+func reminderRecipients(task *model.Tasks) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range append([]string{task.CreatedBy}, task.Assignees...) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// emailReminder ส่งอีเมลเฉพาะผู้ใช้ที่ยังใช้งานอยู่ วันครบกำหนดแสดงตาม timezone ของผู้รับ
+func emailReminder(ctx context.Context, firestoreClient *firestore.Client, mail *mailer.Queue, task *model.Tasks, userIDs []string) {
+	refs := make([]*firestore.DocumentRef, 0, len(userIDs))
+	for _, id := range userIDs {
+		refs = append(refs, firestoreClient.Collection("Users").Doc(id))
+	}
+	docs, err := firestoreClient.GetAll(ctx, refs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reminder recipients", "task_id", task.TaskID, "error", err)
+		return
+	}
+
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var user model.User
+		if err := doc.DataTo(&user); err != nil || user.Email == "" || user.Active != model.AccountActive {
+			continue
+		}
+		if err := mail.Enqueue(mailer.Message{
+			To:      user.Email,
+			Subject: "Reminder: " + task.TaskName,
+			Body:    reminderEmailContent(task, UserLocation(&user)),
+		}); err != nil {
+			slog.WarnContext(ctx, "failed to queue reminder email", "task_id", task.TaskID, "error", err)
+		}
+	}
+}
+
+func reminderEmailContent(task *model.Tasks, loc *time.Location) string {
+	body := "<p>This is a reminder for your task <strong>" + html.EscapeString(task.TaskName) + "</strong>.</p>"
+	if task.DueDate != nil {
+		due := task.DueDate.In(loc).Format("2 Jan 2006 15:04")
+		if task.AllDay {
+			due = task.DueDate.UTC().Format("2 Jan 2006")
+		}
+		body += "<p>Due: " + due + "</p>"
+	}
+	if task.Description != "" {
+		body += "<p>" + html.EscapeString(task.Description) + "</p>"
+	}
+	return body
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"myapp/model"
+)
+
+func TestReminderFireTime(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		v := time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+		return &v
+	}
+	daily := PatternDaily
+
+	tests := []struct {
+		name     string
+		reminder model.Notification
+		want     *time.Time
+	}{
+		{name: "no time", reminder: model.Notification{}},
+		{name: "due date", reminder: model.Notification{DueDate: at(12, 9)}, want: at(12, 9)},
+		{name: "before due date wins over due date",
+			reminder: model.Notification{DueDate: at(12, 9), BeforeDueDate: at(11, 9)}, want: at(11, 9)},
+		{name: "snooze wins over everything",
+			reminder: model.Notification{DueDate: at(12, 9), BeforeDueDate: at(11, 9), Snooze: at(11, 10)}, want: at(11, 10)},
+		{name: "recurring and never sent",
+			reminder: model.Notification{DueDate: at(12, 9), RecurringPattern: &daily}, want: at(12, 9)},
+		{name: "recurring moves past the last send",
+			reminder: model.Notification{DueDate: at(12, 9), RecurringPattern: &daily, LastSentAt: at(14, 9)}, want: at(15, 9)},
+		{name: "one-off ignores the last send",
+			reminder: model.Notification{DueDate: at(12, 9), LastSentAt: at(14, 9)}, want: at(12, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReminderFireTime(&tt.reminder, time.UTC)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Fatalf("ReminderFireTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShiftReminder(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		v := time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+		return &v
+	}
+	now := *at(10, 12)
+
+	tests := []struct {
+		name       string
+		reminder   model.Notification
+		oldDue     *time.Time
+		newDue     *time.Time
+		wantShift  bool
+		wantDue    *time.Time
+		wantBefore *time.Time
+		wantSend   string
+	}{
+		{
+			name:      "tied reminder moves to the new due date",
+			reminder:  model.Notification{DueDate: at(12, 9), Send: "1"},
+			oldDue:    at(12, 9),
+			newDue:    at(15, 9),
+			wantShift: true,
+			wantDue:   at(15, 9),
+			wantSend:  "0",
+		},
+		{
+			name:       "before due date keeps its offset",
+			reminder:   model.Notification{DueDate: at(12, 9), BeforeDueDate: at(11, 9), Send: "0"},
+			oldDue:     at(12, 9),
+			newDue:     at(14, 10),
+			wantShift:  true,
+			wantDue:    at(14, 10),
+			wantBefore: at(13, 10),
+			wantSend:   "0",
+		},
+		{
+			name:       "sent reminder stays sent when the new time has passed",
+			reminder:   model.Notification{DueDate: at(12, 9), BeforeDueDate: at(11, 9), Send: "1"},
+			oldDue:     at(12, 9),
+			newDue:     at(10, 18),
+			wantShift:  true,
+			wantDue:    at(10, 18),
+			wantBefore: at(9, 18),
+			wantSend:   "1",
+		},
+		{
+			name:      "custom reminder time is left alone",
+			reminder:  model.Notification{DueDate: at(11, 8), Send: "1"},
+			oldDue:    at(12, 9),
+			newDue:    at(15, 9),
+			wantShift: false,
+			wantDue:   at(11, 8),
+			wantSend:  "1",
+		},
+		{
+			name:      "due date removed",
+			reminder:  model.Notification{DueDate: at(12, 9), Send: "0"},
+			oldDue:    at(12, 9),
+			wantShift: false,
+			wantDue:   at(12, 9),
+			wantSend:  "0",
+		},
+		{
+			name:      "due date unchanged",
+			reminder:  model.Notification{DueDate: at(12, 9), Send: "1"},
+			oldDue:    at(12, 9),
+			newDue:    at(12, 9),
+			wantShift: false,
+			wantDue:   at(12, 9),
+			wantSend:  "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminder := tt.reminder
+			if got := ShiftReminder(&reminder, tt.oldDue, tt.newDue, now); got != tt.wantShift {
+				t.Fatalf("ShiftReminder() = %v, want %v", got, tt.wantShift)
+			}
+			if !equalTime(reminder.DueDate, tt.wantDue) {
+				t.Errorf("duedate = %v, want %v", reminder.DueDate, tt.wantDue)
+			}
+			if !equalTime(reminder.BeforeDueDate, tt.wantBefore) {
+				t.Errorf("beforeduedate = %v, want %v", reminder.BeforeDueDate, tt.wantBefore)
+			}
+			if reminder.Send != tt.wantSend {
+				t.Errorf("send = %q, want %q", reminder.Send, tt.wantSend)
+			}
+		})
+	}
+}
+
+func TestReminderRecipients(t *testing.T) {
+	tests := []struct {
+		name string
+		task model.Tasks
+		want []string
+	}{
+		{name: "creator only", task: model.Tasks{CreatedBy: "u1"}, want: []string{"u1"}},
+		{name: "creator and assignees", task: model.Tasks{CreatedBy: "u1", Assignees: []string{"u2", "u3"}}, want: []string{"u1", "u2", "u3"}},
+		{name: "creator also assigned", task: model.Tasks{CreatedBy: "u1", Assignees: []string{"u2", "u1", "u2"}}, want: []string{"u1", "u2"}},
+		{name: "no creator", task: model.Tasks{Assignees: []string{"u2"}}, want: []string{"u2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reminderRecipients(&tt.task); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("reminderRecipients() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderEmailContent(t *testing.T) {
+	bangkok := mustLoad(t, "Asia/Bangkok")
+	due := time.Date(2024, 3, 12, 2, 30, 0, 0, time.UTC)
+	task := model.Tasks{TaskName: "Pay <rent>", Description: "before & after", DueDate: &due}
+
+	body := reminderEmailContent(&task, bangkok)
+	for _, want := range []string{"Pay &lt;rent&gt;", "Due: 12 Mar 2024 09:30", "before &amp; after"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body %q does not contain %q", body, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"myapp/model"
+	"myapp/search"
+
+	"cloud.google.com/go/firestore"
+)
+
+func TaskSearchDocument(task *model.Tasks) search.Document {
+	return search.Document{
+		Kind:      search.KindTask,
+		ID:        task.TaskID,
+		BoardID:   task.BoardID,
+		Title:     task.TaskName,
+		Body:      task.Description,
+		UpdatedAt: task.UpdatedAt,
+	}
+}
+
+func BoardSearchDocument(board *model.Board) search.Document {
+	return search.Document{
+		Kind:      search.KindBoard,
+		ID:        board.BoardID,
+		BoardID:   board.BoardID,
+		Title:     board.BoardName,
+		UpdatedAt: board.UpdatedAt,
+	}
+}
+
+// IndexTasks อัปเดตดัชนีค้นหาหลังบันทึก task สำเร็จแล้ว
+// ดัชนีไม่ใช่ข้อมูลหลัก ถ้าล้มเหลวจะบันทึก log ไว้และใช้ cmd/reindex-search ซ่อมภายหลัง
+func IndexTasks(ctx context.Context, index search.Index, tasks ...model.Tasks) {
+	docs := make([]search.Document, 0, len(tasks))
+	for i := range tasks {
+		docs = append(docs, TaskSearchDocument(&tasks[i]))
+	}
+	if err := index.Put(ctx, docs...); err != nil {
+		slog.ErrorContext(ctx, "failed to index tasks", "count", len(docs), "error", err)
+	}
+}
+
+func IndexBoard(ctx context.Context, index search.Index, board *model.Board) {
+	if err := index.Put(ctx, BoardSearchDocument(board)); err != nil {
+		slog.ErrorContext(ctx, "failed to index board", "board_id", board.BoardID, "error", err)
+	}
+}
+
+// ReindexTasks อ่าน task ล่าสุดจาก Firestore แล้วอัปเดตดัชนี task ที่ถูกลบไปแล้วจะถูกนำออก
+func ReindexTasks(ctx context.Context, firestoreClient *firestore.Client, index search.Index, taskIDs ...string) {
+	if len(taskIDs) == 0 {
+		return
+	}
+	refs := make([]*firestore.DocumentRef, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		refs = append(refs, firestoreClient.Collection("Tasks").Doc(id))
+	}
+	docs, err := firestoreClient.GetAll(ctx, refs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to reindex tasks", "error", err)
+		return
+	}
+
+	var tasks []model.Tasks
+	var removed []string
+	for _, doc := range docs {
+		if !doc.Exists() {
+			removed = append(removed, doc.Ref.ID)
+			continue
+		}
+		var task model.Tasks
+		if err := doc.DataTo(&task); err != nil {
+			slog.ErrorContext(ctx, "failed to reindex task", "task_id", doc.Ref.ID, "error", err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	IndexTasks(ctx, index, tasks...)
+	RemoveFromIndex(ctx, index, search.KindTask, removed...)
+}
+
+func RemoveFromIndex(ctx context.Context, index search.Index, kind search.Kind, ids ...string) {
+	if err := index.Delete(ctx, kind, ids...); err != nil {
+		slog.ErrorContext(ctx, "failed to remove from search index", "kind", kind, "count", len(ids), "error", err)
+	}
+}
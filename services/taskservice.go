@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"myapp/apperror"
+	"myapp/model"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func GetTask(ctx context.Context, firestoreClient *firestore.Client, taskID string) (*model.Tasks, error) {
+	docSnap, err := firestoreClient.Collection("Tasks").Doc(taskID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	var task model.Tasks
+	if err := docSnap.DataTo(&task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTaskForUser ดึง task และตรวจสอบว่าผู้ใช้มีสิทธิ์ในบอร์ดของ task นั้น
+func GetTaskForUser(ctx context.Context, firestoreClient *firestore.Client, taskID, userID string) (*model.Tasks, error) {
+	task, _, err := GetTaskAndBoardForUser(ctx, firestoreClient, taskID, userID)
+	return task, err
+}
+
+// GetTaskAndBoardForUser เหมือน GetTaskForUser แต่คืนค่าบอร์ดของ task มาด้วย
+func GetTaskAndBoardForUser(ctx context.Context, firestoreClient *firestore.Client, taskID, userID string) (*model.Tasks, *model.Board, error) {
+	task, err := GetTask(ctx, firestoreClient, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	board, err := GetBoardForUser(ctx, firestoreClient, task.BoardID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, board, nil
+}
+
+func ChecklistCollection(firestoreClient *firestore.Client, taskID string) *firestore.CollectionRef {
+	return firestoreClient.Collection("Tasks").Doc(taskID).Collection("Checklists")
+}
+
+// GetChecklists ดึงรายการ checklist ของ task เรียงตามลำดับ
+func GetChecklists(ctx context.Context, firestoreClient *firestore.Client, taskID string) ([]model.Checklist, error) {
+	docs, err := ChecklistCollection(firestoreClient, taskID).
+		OrderBy("order", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	checklists := make([]model.Checklist, 0, len(docs))
+	for _, doc := range docs {
+		var item model.Checklist
+		if err := doc.DataTo(&item); err != nil {
+			return nil, err
+		}
+		checklists = append(checklists, item)
+	}
+	return checklists, nil
+}
+
+// ChecklistProgress คำนวณเปอร์เซ็นต์ความคืบหน้าจากจำนวน checklist ที่ทำเสร็จแล้ว
+func ChecklistProgress(checklists []model.Checklist) int {
+	if len(checklists) == 0 {
+		return 0
+	}
+
+	done := 0
+	for _, item := range checklists {
+		if item.Status == "1" {
+			done++
+		}
+	}
+	return done * 100 / len(checklists)
+}
+
+// NewTask task ที่จะสร้างพร้อม reminder ซึ่งต้องบันทึกไปด้วยกัน
+type NewTask struct {
+	Task      model.Tasks
+	Reminders []model.Notification
+}
+
+// ValidateReminder ตรวจสอบ reminder ก่อนบันทึก
+func ValidateReminder(task *model.Tasks, reminder *model.Notification) error {
+	if reminder.RecurringPattern != nil && *reminder.RecurringPattern != "" && NormalizePattern(reminder.RecurringPattern) == "" {
+		return apperror.Invalid(fmt.Sprintf("unsupported pattern %q, expected daily, weekly, monthly or yearly", *reminder.RecurringPattern))
+	}
+	if reminder.BeforeDueDate != nil {
+		due := reminder.DueDate
+		if due == nil {
+			due = task.DueDate
+		}
+		if due == nil {
+			return apperror.Invalid("before_due_date requires a due date")
+		}
+		if reminder.BeforeDueDate.After(*due) {
+			return apperror.Invalid("before_due_date must not be after the due date")
+		}
+	}
+	return nil
+}
+
+// CreateTasks บันทึก task และ reminder ทั้งหมดใน transaction เดียว
+// ถ้ามีรายการใดล้มเหลวจะไม่มีข้อมูลใดถูกบันทึก (ไม่มี task หรือ reminder ค้าง)
+func CreateTasks(ctx context.Context, firestoreClient *firestore.Client, items []NewTask) error {
+	now := time.Now()
+	for i := range items {
+		task := &items[i].Task
+		if task.TaskID == "" {
+			task.TaskID = uuid.New().String()
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		// task ที่สร้างพร้อมกันเรียงตามลำดับในรายการ
+		if task.Position == 0 {
+			task.Position = InitialPosition(now) + float64(i)/1000
+		}
+		task.UpdatedAt = now
+		for j := range items[i].Reminders {
+			reminder := &items[i].Reminders[j]
+			if reminder.NotificationID == "" {
+				reminder.NotificationID = uuid.New().String()
+			}
+			reminder.TaskID = task.TaskID
+			if reminder.Send == "" {
+				reminder.Send = "0"
+			}
+			reminder.Updatedat = now
+		}
+	}
+
+	return firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, item := range items {
+			if err := tx.Create(firestoreClient.Collection("Tasks").Doc(item.Task.TaskID), item.Task); err != nil {
+				return err
+			}
+			for _, reminder := range item.Reminders {
+				if err := tx.Create(firestoreClient.Collection("NotificationTasks").Doc(reminder.NotificationID), reminder); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
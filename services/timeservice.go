@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"myapp/model"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// DefaultTimezone ใช้เมื่อผู้ใช้ยังไม่ได้ตั้งค่า timezone
+const DefaultTimezone = "Asia/Bangkok"
+
+// localLayouts รูปแบบวันเวลาที่ไม่มี offset จะตีความตาม timezone ของผู้ใช้
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	return time.LoadLocation(name)
+}
+
+// UserLocation คืนค่า timezone ของผู้ใช้ ถ้าค่าไม่ถูกต้องจะใช้ DefaultTimezone
+func UserLocation(user *model.User) *time.Location {
+	loc, err := LoadLocation(user.Timezone)
+	if err != nil {
+		loc, _ = LoadLocation(DefaultTimezone)
+	}
+	return loc
+}
+
+// GetUserLocation ดึงผู้ใช้จาก Firestore แล้วคืนค่า timezone ของผู้ใช้
+func GetUserLocation(ctx context.Context, firestoreClient *firestore.Client, userID string) (*time.Location, error) {
+	docSnap, err := GetUserDataByUserid(ctx, firestoreClient, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	if err := docSnap.DataTo(&user); err != nil {
+		return nil, err
+	}
+	return UserLocation(&user), nil
+}
+
+// ParseTaskTime แปลงค่าวันเวลาที่ client ส่งมา
+// - allDay: รับ "2006-01-02" (หรือ RFC3339 ซึ่งจะใช้วันที่ตาม loc) แล้วเก็บเป็นเที่ยงคืน UTC ของวันนั้น
+// - ไม่ใช่ allDay: รับ RFC3339 หรือวันเวลาที่ไม่มี offset ซึ่งจะตีความตาม loc
+func ParseTaskTime(value string, allDay bool, loc *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if allDay {
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return &t, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", value)
+		}
+		day := AllDayDate(t, loc)
+		return &day, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid datetime %q", value)
+}
+
+// StartOfDay คืนค่าเที่ยงคืนของวันที่ t ตาม loc
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// AllDayDate วันที่ของ t ตาม loc ในรูปแบบที่เก็บของ task แบบทั้งวัน คือเที่ยงคืน UTC ของวันนั้น
+// ค่านี้เป็นวันที่ล้วน ๆ ไม่ผูกกับ timezone ของผู้สร้าง ผู้ใช้ทุกคนจึงเห็นวันเดียวกัน
+func AllDayDate(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// AllDayIn เที่ยงคืนตาม loc ของวันที่ที่เก็บไว้ด้วย AllDayDate ใช้เมื่อต้องเทียบกับเวลาจริงของผู้ใช้
+func AllDayIn(date time.Time, loc *time.Location) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+// TaskDueAt เวลาจริงที่ task ครบกำหนดสำหรับผู้ใช้ใน loc task แบบทั้งวันครบกำหนดตอนเที่ยงคืนของวันนั้น
+func TaskDueAt(task *model.Tasks, loc *time.Location) *time.Time {
+	if task.DueDate == nil || !task.AllDay {
+		return task.DueDate
+	}
+	due := AllDayIn(*task.DueDate, loc)
+	return &due
+}
+
+// FormatTaskTime แปลงเวลาเป็น string ตาม timezone ของผู้ใช้
+// all-day แสดงเฉพาะวันที่ที่เก็บไว้โดยไม่แปลง timezone
+func FormatTaskTime(t *time.Time, allDay bool, loc *time.Location) string {
+	if t == nil {
+		return ""
+	}
+	if allDay {
+		return t.UTC().Format("2006-01-02")
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// CompletionTime คำนวณค่า CompletedAt ใหม่เมื่อ task ย้ายเข้าหรือออกจากคอลัมน์ done
+func CompletionTime(wasDone, isDone bool, current *time.Time, now time.Time) *time.Time {
+	if isDone {
+		if wasDone && current != nil {
+			return current
+		}
+		return &now
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTaskTime(t *testing.T) {
+	bangkok := mustLoad(t, "Asia/Bangkok")
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		value   string
+		allDay  bool
+		loc     *time.Location
+		want    time.Time
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "empty", value: "", wantNil: true, loc: bangkok},
+		{name: "all-day date is stored as UTC midnight", value: "2024-03-12", allDay: true, loc: bangkok,
+			want: time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "all-day date does not depend on timezone", value: "2024-03-12", allDay: true, loc: newYork,
+			want: time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "all-day RFC3339 uses the local date", value: "2024-03-12T20:00:00Z", allDay: true, loc: bangkok,
+			want: time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "RFC3339", value: "2024-03-12T09:30:00+07:00", loc: newYork,
+			want: time.Date(2024, 3, 12, 2, 30, 0, 0, time.UTC)},
+		{name: "local time without offset", value: "2024-03-12T09:30", loc: bangkok,
+			want: time.Date(2024, 3, 12, 9, 30, 0, 0, bangkok)},
+		{name: "invalid all-day", value: "12/03/2024", allDay: true, loc: bangkok, wantErr: true},
+		{name: "invalid datetime", value: "tomorrow", loc: bangkok, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTaskTime(tt.value, tt.allDay, tt.loc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("got %v, want nil: %v", got, tt.wantNil)
+			}
+			if got != nil && !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTaskTime(t *testing.T) {
+	bangkok := mustLoad(t, "Asia/Bangkok")
+	newYork := mustLoad(t, "America/New_York")
+	date := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+	instant := time.Date(2024, 3, 12, 2, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		t      *time.Time
+		allDay bool
+		loc    *time.Location
+		want   string
+	}{
+		{name: "nil", loc: bangkok, want: ""},
+		{name: "all-day east of UTC", t: &date, allDay: true, loc: bangkok, want: "2024-03-12"},
+		{name: "all-day west of UTC", t: &date, allDay: true, loc: newYork, want: "2024-03-12"},
+		{name: "timed in Bangkok", t: &instant, loc: bangkok, want: "2024-03-12T09:30:00+07:00"},
+		{name: "timed in New York", t: &instant, loc: newYork, want: "2024-03-11T22:30:00-04:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTaskTime(tt.t, tt.allDay, tt.loc); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllDayRoundTrip(t *testing.T) {
+	for _, name := range []string{"Asia/Bangkok", "America/New_York", "Pacific/Auckland", "UTC"} {
+		t.Run(name, func(t *testing.T) {
+			loc := mustLoad(t, name)
+			date := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+			local := AllDayIn(date, loc)
+			if y, m, d := local.Date(); y != 2024 || m != 3 || d != 12 || local.Hour() != 0 || local.Location() != loc {
+				t.Fatalf("AllDayIn = %v, want local midnight of 2024-03-12", local)
+			}
+			if back := AllDayDate(local, loc); !back.Equal(date) {
+				t.Fatalf("AllDayDate(AllDayIn(date)) = %v, want %v", back, date)
+			}
+		})
+	}
+}
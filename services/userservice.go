@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
-	"errors"
+	"myapp/apperror"
 
 	"cloud.google.com/go/firestore"
 )
 
+var ErrUserNotFound = apperror.New(apperror.CodeUserNotFound)
+
 func UserExist(ctx context.Context, firestoreClient *firestore.Client, email string) (bool, error) {
 	usersCollection := firestoreClient.Collection("Users")
 	query := usersCollection.Where("email", "==", email).Limit(1)
@@ -32,7 +34,7 @@ func GetUserExist(ctx context.Context, firestoreClient *firestore.Client, email
 	}
 
 	if len(docs) == 0 {
-		return nil, errors.New("user not found") // email ไม่มีในระบบ
+		return nil, ErrUserNotFound // email ไม่มีในระบบ
 	}
 
 	// คืน docRef ของ document ที่เจอ
@@ -50,7 +52,7 @@ func GetUserData(ctx context.Context, firestoreClient *firestore.Client, email s
 	}
 
 	if len(docs) == 0 {
-		return nil, errors.New("user not found") // email ไม่มีในระบบ
+		return nil, ErrUserNotFound // email ไม่มีในระบบ
 	}
 
 	// คืน DocumentSnapshot ของ document ที่เจอ
@@ -68,7 +70,7 @@ func GetUserDataByUserid(ctx context.Context, firestoreClient *firestore.Client,
 	}
 
 	if len(docs) == 0 {
-		return nil, errors.New("user not found") // email ไม่มีในระบบ
+		return nil, ErrUserNotFound // email ไม่มีในระบบ
 	}
 
 	// คืน DocumentSnapshot ของ document ที่เจอ
@@ -0,0 +1,88 @@
+// Package tracing ตั้งค่า OpenTelemetry tracer ของแอปและมีตัวช่วยสร้าง span
+// ตัว sampler, endpoint และ header ของ OTLP ใช้ environment variable มาตรฐาน OTEL_* ของ SDK
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"myapp/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	// ServiceName ใช้เมื่อไม่ได้กำหนด OTEL_SERVICE_NAME
+	ServiceName = "myapp"
+
+	instrumentationName = "myapp"
+)
+
+// Setup ตั้งค่า tracer provider และ propagator (W3C traceparent) ของทั้งแอป
+// คืนฟังก์ชันที่ต้องเรียกตอนปิด server เพื่อส่ง span ที่ค้างอยู่ให้หมด
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Tracing.Exporter {
+	case ExporterNone, "":
+		// ไม่ตั้ง provider เลย span ทั้งหมดเป็น no-op แต่ยังส่งต่อ traceparent ได้
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Tracing.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Tracing.Exporter, err)
+	}
+
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(ServiceName), attribute.String("deployment.environment", cfg.Env)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start เปิด span ลูกของ span ใน ctx ผู้เรียกต้องปิดด้วย End
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End บันทึก error (ถ้ามี) แล้วปิด span ใช้คู่กับ defer และ named return เช่น
+//
+//	ctx, span := tracing.Start(ctx, "smtp.Send")
+//	defer func() { tracing.End(span, err) }()
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}